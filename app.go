@@ -8,6 +8,7 @@ import (
 
 	"ggami-go/internal/application"
 	"ggami-go/internal/builder"
+	"ggami-go/internal/domain"
 	"ggami-go/internal/generator"
 	"ggami-go/internal/modules"
 )
@@ -47,9 +48,10 @@ func (a *App) GetModules() []modules.ModuleDef {
 	return modules.Registry
 }
 
-// GenerateProject generates a project with the given config and language
-func (a *App) GenerateProject(config generator.ProjectConfig, lang string) map[string]interface{} {
-	result, err := application.GenerateProject(config, lang)
+// GenerateProject generates a project with the given config and language.
+// allowDestructive confirms schema changes that can lose data (see DiffProjectSchema).
+func (a *App) GenerateProject(config generator.ProjectConfig, lang string, allowDestructive bool) map[string]interface{} {
+	result, err := application.GenerateProjectWithOptions(config, lang, application.GenerateOptions{AllowDestructive: allowDestructive})
 	if err != nil {
 		return map[string]interface{}{
 			"success": false,
@@ -62,6 +64,53 @@ func (a *App) GenerateProject(config generator.ProjectConfig, lang string) map[s
 	}
 }
 
+// RegenerateProject regenerates into an existing target, keeping files the
+// user modified since the last generation
+func (a *App) RegenerateProject(config generator.ProjectConfig, lang string, allowDestructive bool) map[string]interface{} {
+	result, err := application.GenerateProjectWithOptions(config, lang, application.GenerateOptions{Merge: true, AllowDestructive: allowDestructive})
+	if err != nil {
+		return map[string]interface{}{
+			"success": false,
+			"message": fmt.Sprintf("Regeneration failed: %v", err),
+		}
+	}
+	return map[string]interface{}{
+		"success": true,
+		"message": result,
+	}
+}
+
+// PlanProject performs a dry run and returns the files generation would write,
+// overwrite or delete in the target directory
+func (a *App) PlanProject(config generator.ProjectConfig, lang string) (*domain.GenerationPlan, error) {
+	return application.PlanProject(config, lang, application.GenerateOptions{})
+}
+
+// DiffProjectSchema compares config with the config the target directory was
+// last generated with. Returns nil if there is nothing to compare against.
+func (a *App) DiffProjectSchema(config generator.ProjectConfig) (*domain.SchemaDiff, error) {
+	return application.DiffProjectSchema(config)
+}
+
+// IntrospectDatabase reads the schema of the database configured in config
+// (dbType and connection fields; dbName is the file for SQLite) and returns
+// the models it maps to, with warnings for anything that could not be mapped.
+func (a *App) IntrospectDatabase(config generator.ProjectConfig) (*domain.ModelImport, error) {
+	return application.IntrospectDatabase(config, "")
+}
+
+// ImportDDL maps the tables created by a SQL DDL script to models. An empty
+// dbType guesses the dialect from the script.
+func (a *App) ImportDDL(script string, dbType string) *domain.ModelImport {
+	return application.ImportDDL(script, domain.DBType(dbType))
+}
+
+// ImportOpenAPI maps the object schemas of an OpenAPI 3 document or JSON
+// Schema file to models. name titles a root schema without a title.
+func (a *App) ImportOpenAPI(document string, name string) (*domain.ModelImport, error) {
+	return application.ImportOpenAPI(document, name)
+}
+
 // --- Builder Methods ---
 
 // CreateBuilderProject creates a new builder project
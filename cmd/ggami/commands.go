@@ -0,0 +1,375 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ggami-go/internal/application"
+	"ggami-go/internal/builder"
+	"ggami-go/internal/domain"
+	"ggami-go/internal/modules"
+)
+
+// runGenerate: ggami generate -config project.json [-lang go] [-out ./dist]
+func runGenerate(args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("generate", stderr)
+	configPath := fs.String("config", "", "path to a ProjectConfig JSON or YAML file (required)")
+	lang := fs.String("lang", "go", `target language ("go" or "node")`)
+	out := fs.String("out", "", "output directory (overrides targetPath in the config)")
+	dryRun := fs.Bool("dry-run", false, "render in memory and print the plan without writing anything")
+	merge := fs.Bool("merge", false, "keep files modified since the last generation instead of replacing the target")
+	allowDestructive := fs.Bool("allow-destructive", false, "confirm schema changes that can lose data (dropped tables or columns, narrowed types)")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	config, code := loadConfig(fs, *configPath, stderr)
+	if code != exitOK {
+		return code
+	}
+	if *out != "" {
+		config.TargetPath = *out
+	}
+
+	if *dryRun {
+		plan, err := application.PlanProject(config, *lang, application.GenerateOptions{
+			Progress:         progressPrinter(stdout),
+			Merge:            *merge,
+			AllowDestructive: *allowDestructive,
+		})
+		if err != nil {
+			return reportFailure(stderr, err)
+		}
+		printPlan(stdout, plan)
+		return exitOK
+	}
+
+	result, err := application.GenerateProjectWithOptions(config, *lang, application.GenerateOptions{
+		Progress:         progressPrinter(stdout),
+		Merge:            *merge,
+		AllowDestructive: *allowDestructive,
+	})
+	if err != nil {
+		return reportFailure(stderr, err)
+	}
+	fmt.Fprintln(stdout, result)
+	return exitOK
+}
+
+// runValidate: ggami validate -config project.json [-lang go]
+func runValidate(args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("validate", stderr)
+	configPath := fs.String("config", "", "path to a ProjectConfig JSON or YAML file (required)")
+	lang := fs.String("lang", "go", `target language ("go" or "node")`)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	config, code := loadConfig(fs, *configPath, stderr)
+	if code != exitOK {
+		return code
+	}
+
+	if err := application.ValidateProject(config, *lang, progressPrinter(stdout)); err != nil {
+		return reportFailure(stderr, err)
+	}
+	fmt.Fprintf(stdout, "Config %s is valid\n", *configPath)
+	return exitOK
+}
+
+// runDiff: ggami diff -config project.json [-out ./dist]
+func runDiff(args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("diff", stderr)
+	configPath := fs.String("config", "", "path to a ProjectConfig JSON or YAML file (required)")
+	out := fs.String("out", "", "generated target directory (overrides targetPath in the config)")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	config, code := loadConfig(fs, *configPath, stderr)
+	if code != exitOK {
+		return code
+	}
+	if *out != "" {
+		config.TargetPath = *out
+	}
+
+	diff, err := application.DiffProjectSchema(config)
+	if err != nil {
+		return reportFailure(stderr, err)
+	}
+	if diff == nil {
+		fmt.Fprintf(stdout, "%s has no recorded config; the next generation creates the initial migration\n", config.TargetPath)
+		return exitOK
+	}
+	printSchemaDiff(stdout, diff)
+	return exitOK
+}
+
+// runIntrospect: ggami introspect -db sqlite -dsn ./app.db [-config project.yaml] [-out models.yaml]
+// or ggami introspect -ddl schema.sql [-db mssql] [-out models.yaml]
+// or ggami introspect -openapi openapi.yaml [-out models.yaml]
+func runIntrospect(args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("introspect", stderr)
+	configPath := fs.String("config", "", "ProjectConfig whose connection settings are used and whose models are replaced")
+	dbType := fs.String("db", "", `database type ("sqlite", "postgres", "mysql" or "mssql"; default from -config or sqlite)`)
+	dsn := fs.String("dsn", "", "connection string, or the database file for sqlite (overrides -config)")
+	sslMode := fs.String("sslmode", "", `postgres sslmode for the connection fields of -config ("disable", "require", "verify-full", ...; default prefer)`)
+	ddl := fs.String("ddl", "", "read CREATE TABLE statements from this SQL script instead of a database")
+	openapi := fs.String("openapi", "", "read schemas from this OpenAPI 3 document or JSON Schema file (.yaml or .json) instead of a database")
+	out := fs.String("out", "", "write the resulting config to this .yaml or .json file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *configPath == "" && *dsn == "" && *ddl == "" && *openapi == "" {
+		fmt.Fprintln(stderr, "ggami introspect: -dsn, -ddl, -openapi or -config is required")
+		fs.Usage()
+		return exitUsage
+	}
+
+	config := domain.ProjectConfig{GormMode: true}
+	if *configPath != "" {
+		var code int
+		if config, code = loadConfig(fs, *configPath, stderr); code != exitOK {
+			return code
+		}
+	}
+	if *dbType != "" {
+		config.DBType = domain.DBType(*dbType)
+	}
+	if config.DBType == "" && *ddl == "" {
+		config.DBType = domain.DBTypeSQLite
+	}
+
+	var result *domain.ModelImport
+	var err error
+	switch {
+	case *openapi != "":
+		result, err = application.ImportOpenAPIFile(*openapi)
+	case *ddl != "":
+		// the dialect is guessed from the script unless -db or the config names it
+		result, config.DBType, err = application.ImportDDLFile(*ddl, config.DBType)
+	case *dsn != "":
+		result, err = application.IntrospectDSN(config.DBType, *dsn)
+	default:
+		result, err = application.IntrospectDatabase(config, *sslMode)
+	}
+	if err != nil {
+		return reportFailure(stderr, err)
+	}
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(stderr, "warning: %s\n", warning)
+	}
+
+	config.GormMode = true
+	config.Models = result.Models
+	if config.ProjectName == "" {
+		switch {
+		case *openapi != "":
+			config.ProjectName, _, _ = strings.Cut(filepath.Base(*openapi), ".")
+		case *ddl != "":
+			config.ProjectName = strings.TrimSuffix(filepath.Base(*ddl), filepath.Ext(*ddl))
+		case config.DBType == domain.DBTypeSQLite && *dsn != "":
+			config.ProjectName = strings.TrimSuffix(filepath.Base(*dsn), filepath.Ext(*dsn))
+		}
+	}
+	format := "yaml"
+	if strings.EqualFold(filepath.Ext(*out), ".json") {
+		format = "json"
+	}
+	data, err := application.MarshalProjectConfig(config, format)
+	if err != nil {
+		return reportFailure(stderr, err)
+	}
+	if *out == "" {
+		stdout.Write(data)
+		return exitOK
+	}
+	if err := os.WriteFile(*out, data, 0644); err != nil {
+		return reportFailure(stderr, err)
+	}
+	fmt.Fprintf(stdout, "Wrote %d models to %s\n", len(result.Models), *out)
+	return exitOK
+}
+
+// runListModules: ggami list-modules
+func runListModules(args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("list-modules", stderr)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	for _, mod := range modules.Registry {
+		fmt.Fprintf(stdout, "%-16s %-8s %s\n", mod.ID, mod.Category, mod.Name)
+		if mod.Description != "" {
+			fmt.Fprintf(stdout, "%-16s %-8s %s\n", "", "", mod.Description)
+		}
+		if len(mod.Dependencies) > 0 {
+			fmt.Fprintf(stdout, "%-16s %-8s requires: %s\n", "", "", strings.Join(mod.Dependencies, ", "))
+		}
+	}
+	return exitOK
+}
+
+// runExportBuilder: ggami export-builder -project site.ggami.json -out ./site
+func runExportBuilder(args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("export-builder", stderr)
+	projectPath := fs.String("project", "", "path to a .ggami.json builder project (required)")
+	out := fs.String("out", "", "output directory for the static HTML (required)")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *projectPath == "" || *out == "" {
+		fmt.Fprintln(stderr, "ggami export-builder: -project and -out are required")
+		fs.Usage()
+		return exitUsage
+	}
+
+	pm := builder.NewProjectManager()
+	if _, err := pm.LoadProject(*projectPath); err != nil {
+		fmt.Fprintf(stderr, "ggami export-builder: %v\n", err)
+		return exitError
+	}
+	if err := pm.ExportToHTML(*out); err != nil {
+		fmt.Fprintf(stderr, "ggami export-builder: %v\n", err)
+		return exitError
+	}
+	fmt.Fprintf(stdout, "Exported %s to %s\n", *projectPath, *out)
+	return exitOK
+}
+
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet("ggami "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs
+}
+
+func loadConfig(fs *flag.FlagSet, path string, stderr io.Writer) (domain.ProjectConfig, int) {
+	if path == "" {
+		fmt.Fprintf(stderr, "%s: -config is required\n", fs.Name())
+		fs.Usage()
+		return domain.ProjectConfig{}, exitUsage
+	}
+	config, err := application.LoadProjectConfig(path)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", fs.Name(), err)
+		return config, exitError
+	}
+	return config, exitOK
+}
+
+// progressPrinter prints one line per finished, failed or rolled-back step
+func progressPrinter(w io.Writer) application.ProgressFunc {
+	return func(ev application.StepEvent) {
+		switch ev.Status {
+		case application.StepCompleted:
+			fmt.Fprintf(w, "[%d/%d] %s ok\n", ev.Index, ev.Total, ev.Step)
+		case application.StepFailed:
+			fmt.Fprintf(w, "[%d/%d] %s FAILED: %v\n", ev.Index, ev.Total, ev.Step, ev.Err)
+		case application.StepRolledBack:
+			fmt.Fprintf(w, "[%d/%d] %s rolled back\n", ev.Index, ev.Total, ev.Step)
+		}
+	}
+}
+
+// printPlan prints one line per planned file followed by a summary
+func printPlan(w io.Writer, plan *domain.GenerationPlan) {
+	counts := make(map[domain.FileAction]int)
+	fmt.Fprintf(w, "\nPlan for %s:\n", plan.TargetDir)
+	for _, f := range plan.Files {
+		counts[f.Action]++
+		fmt.Fprintf(w, "  %-9s %8d  %s  %s\n", f.Action, f.Size, f.SHA256[:12], f.Path)
+	}
+	for _, f := range plan.Deletes {
+		counts[f.Action]++
+		fmt.Fprintf(w, "  %-9s %8d  %s  %s\n", f.Action, f.Size, f.SHA256[:12], f.Path)
+	}
+	fmt.Fprintf(w, "\n%d to create, %d to overwrite, %d unchanged, %d to delete\n",
+		counts[domain.FileCreate], counts[domain.FileOverwrite], counts[domain.FileUnchanged], counts[domain.FileDelete])
+	if plan.Merge {
+		fmt.Fprintf(w, "%d modified files kept, %d written as %s sidecars\n",
+			counts[domain.FileKeep], counts[domain.FileSidecar], domain.SidecarSuffix)
+	}
+	for _, warning := range plan.Warnings {
+		fmt.Fprintf(w, "warning: %s\n", warning)
+	}
+}
+
+// printSchemaDiff prints one line per schema change, destructive ones last
+func printSchemaDiff(w io.Writer, diff *domain.SchemaDiff) {
+	if diff.Empty() {
+		fmt.Fprintln(w, "No schema changes")
+		return
+	}
+	for _, t := range diff.AddedTables {
+		fmt.Fprintf(w, "  + table %s\n", t.Name)
+	}
+	for _, t := range diff.RemovedTables {
+		fmt.Fprintf(w, "  - table %s\n", t.Name)
+	}
+	for _, td := range diff.ChangedTables {
+		fmt.Fprintf(w, "  ~ table %s\n", td.Table)
+		for _, c := range td.AddedColumns {
+			fmt.Fprintf(w, "      + column %s (%s)\n", c.Name, c.Kind)
+		}
+		for _, c := range td.RemovedColumns {
+			fmt.Fprintf(w, "      - column %s\n", c.Name)
+		}
+		for _, ch := range td.ChangedColumns {
+			fmt.Fprintf(w, "      ~ column %s (%s → %s)\n", ch.New.Name, columnSummary(ch.Old), columnSummary(ch.New))
+		}
+		for _, idx := range td.AddedIndexes {
+			fmt.Fprintf(w, "      + index %s\n", idx.Name)
+		}
+		for _, idx := range td.RemovedIndexes {
+			fmt.Fprintf(w, "      - index %s\n", idx.Name)
+		}
+		for _, fk := range td.AddedForeignKeys {
+			fmt.Fprintf(w, "      + foreign key %s\n", fk.Name)
+		}
+		for _, fk := range td.RemovedForeignKeys {
+			fmt.Fprintf(w, "      - foreign key %s\n", fk.Name)
+		}
+	}
+	for _, change := range diff.Destructive {
+		fmt.Fprintf(w, "destructive: %s (generate with -allow-destructive)\n", change)
+	}
+}
+
+// columnSummary describes a column's type and constraints in one phrase
+func columnSummary(c domain.SQLColumn) string {
+	s := c.Kind
+	if c.RawType != "" {
+		s = c.RawType
+	} else if c.Size > 0 {
+		s += fmt.Sprintf("(%d)", c.Size)
+	}
+	if c.NotNull {
+		s += " not null"
+	}
+	if c.Unique {
+		s += " unique"
+	}
+	if c.Default != "" {
+		s += " default " + c.Default
+	}
+	return s
+}
+
+func reportFailure(stderr io.Writer, err error) int {
+	var stepErr *application.StepError
+	if errors.As(err, &stepErr) {
+		fmt.Fprintf(stderr, "ggami: step %s failed: %v\n", stepErr.Step, stepErr.Err)
+		if stepErr.RollbackErr != nil {
+			fmt.Fprintf(stderr, "ggami: rollback of %s also failed: %v\n", stepErr.RollbackOf, stepErr.RollbackErr)
+		}
+		return exitError
+	}
+	fmt.Fprintf(stderr, "ggami: %v\n", err)
+	return exitError
+}
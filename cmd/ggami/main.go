@@ -0,0 +1,79 @@
+// Command ggami is the headless entrypoint for the Ggami generator.
+// It drives the same generation pipeline as the desktop app so projects
+// can be regenerated from scripts and build servers.
+//
+//	ggami generate -config project.json -lang go -out ./dist
+//	ggami validate -config project.yaml
+//	ggami diff -config project.yaml -out ./dist
+//	ggami introspect -db sqlite -dsn ./app.db -out project.yaml
+//	ggami introspect -ddl schema.sql -out project.yaml
+//	ggami introspect -openapi openapi.yaml -out project.yaml
+//	ggami list-modules
+//	ggami export-builder -project site.ggami.json -out ./site
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Exit codes
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdout, stderr io.Writer) int
+}
+
+var commands = []command{
+	{"generate", "Generate a project from a config file", runGenerate},
+	{"validate", "Validate a config file without generating", runValidate},
+	{"diff", "Show schema changes since the last generation of a target", runDiff},
+	{"introspect", "Import models from an existing database schema", runIntrospect},
+	{"list-modules", "List modules available for injection", runListModules},
+	{"export-builder", "Export a visual builder project as static HTML", runExportBuilder},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+
+	name := args[0]
+	if name == "-h" || name == "-help" || name == "--help" || name == "help" {
+		usage(stdout)
+		return exitOK
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd.run(args[1:], stdout, stderr)
+		}
+	}
+
+	fmt.Fprintf(stderr, "ggami: unknown command %q\n\n", name)
+	usage(stderr)
+	return exitUsage
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: ggami <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-16s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'ggami <command> -h' for command flags.")
+}
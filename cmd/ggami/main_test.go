@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeConfig writes a YAML project config to a temporary directory and
+// returns its path
+func writeConfig(t *testing.T, yaml string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "project.yaml")
+	if err := os.WriteFile(path, []byte(yaml), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const validConfig = `projectName: shop
+targetPath: out
+gormMode: true
+dbType: sqlite
+models:
+  - name: Product
+    fields:
+      - {name: ID, type: uint, gormTags: [primaryKey], jsonName: id}
+      - {name: Name, type: string, jsonName: name}
+`
+
+// cycleConfig fails validation: two required belongsTo keys form a cycle
+const cycleConfig = `projectName: shop
+targetPath: out
+gormMode: true
+models:
+  - name: Team
+    fields: [{name: ID, type: uint, gormTags: [primaryKey]}]
+    relations: [{name: Captain, type: belongsTo, model: Player}]
+  - name: Player
+    fields: [{name: ID, type: uint, gormTags: [primaryKey]}]
+    relations: [{name: Team, type: belongsTo, model: Team}]
+`
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string // part of the output, "" to skip the check
+		stderr string
+	}{
+		{"no command", nil, exitUsage, "", "Usage: ggami"},
+		{"unknown command", []string{"frobnicate"}, exitUsage, "", `unknown command "frobnicate"`},
+		{"help", []string{"help"}, exitOK, "Usage: ggami", ""},
+		{"unknown flag", []string{"validate", "-frobnicate"}, exitUsage, "", "flag provided but not defined"},
+		{"missing config", []string{"validate"}, exitUsage, "", "-config is required"},
+		{"unreadable config", []string{"validate", "-config", filepath.Join(t.TempDir(), "missing.yaml")}, exitError, "", "validate:"},
+		{"valid config", []string{"validate", "-config", writeConfig(t, validConfig)}, exitOK, "ValidateConfig ok", ""},
+		{"invalid config", []string{"validate", "-config", writeConfig(t, cycleConfig)}, exitError, "FAILED", "cycle detected"},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if code := run(tt.args, &stdout, &stderr); code != tt.code {
+			t.Errorf("%s: exit code %d, want %d\nstdout: %s\nstderr: %s", tt.name, code, tt.code, &stdout, &stderr)
+		}
+		if !strings.Contains(stdout.String(), tt.stdout) {
+			t.Errorf("%s: stdout %q does not contain %q", tt.name, &stdout, tt.stdout)
+		}
+		if !strings.Contains(stderr.String(), tt.stderr) {
+			t.Errorf("%s: stderr %q does not contain %q", tt.name, &stderr, tt.stderr)
+		}
+	}
+}
+
+func TestGenerateDryRun(t *testing.T) {
+	config := writeConfig(t, validConfig)
+	out := filepath.Join(t.TempDir(), "out")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"generate", "-config", config, "-out", out, "-dry-run"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("exit code %d, want %d\nstderr: %s", code, exitOK, &stderr)
+	}
+	if !strings.Contains(stdout.String(), "Plan for") || !strings.Contains(stdout.String(), "main.go") {
+		t.Errorf("no plan listing main.go in\n%s", &stdout)
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Errorf("-dry-run created %s (stat: %v)", out, err)
+	}
+	entries, err := os.ReadDir(filepath.Dir(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("-dry-run wrote next to the config: %v", entries)
+	}
+}
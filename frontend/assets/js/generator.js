@@ -15,10 +15,30 @@ const FIELD_TYPES = [
     { value: 'float64', label: 'Float64' },
     { value: 'bool', label: 'Bool' },
     { value: 'time.Time', label: 'DateTime' },
+    { value: 'date', label: 'Date' },
+    { value: 'decimal', label: 'Decimal' },
+    { value: 'text', label: 'Text' },
+    { value: 'enum', label: 'Enum' },
+    { value: 'uuid', label: 'UUID' },
+    { value: 'json', label: 'JSON' },
+    { value: 'file', label: 'File' },
+    { value: 'image', label: 'Image' },
 ];
 
 const GORM_TAGS = ['primaryKey', 'unique', 'not null', 'index'];
 
+const MODEL_OPTIONS = [
+    { key: 'timestamps', label: '타임스탬프', help: 'CreatedAt, UpdatedAt 자동 기록' },
+    { key: 'softDelete', label: '소프트 삭제', help: '삭제 시 휴지통으로 이동, 복원 가능' },
+    { key: 'audit', label: '감사 로그', help: '등록·수정·삭제를 audit_logs에 기록' },
+];
+
+// 행 단위 범위 (RBAC 필요): 등록한 사용자의 ID가 들어가는 uint 필드
+const SCOPE_FIELDS = [
+    { key: 'ownerField', label: '소유자 필드', placeholder: 'OwnerID', help: '등록한 사용자만 조회 (전체 열람 역할 제외)' },
+    { key: 'tenantField', label: '테넌트 필드', placeholder: 'TenantID', help: '같은 테넌트의 사용자만 조회' },
+];
+
 // 페이지 로드 시 초기화
 document.addEventListener('DOMContentLoaded', async () => {
     try {
@@ -88,6 +108,11 @@ function onRBACToggle() {
     }
 }
 
+function onMailDriverChange() {
+    const smtp = document.getElementById('mailDriver').value === 'smtp';
+    document.getElementById('mail-smtp').classList.toggle('hidden', !smtp);
+}
+
 // ============ 언어 선택 (Simple 모드) ============
 
 function setLanguage(lang) {
@@ -165,6 +190,100 @@ function addModel() {
     renderRBACMatrix();
 }
 
+// 기존 DB 스키마에서 모델 가져오기 (SQLite는 DB 이름에 파일 경로)
+async function importModels() {
+    const logOutput = document.getElementById('log-output');
+    const logText = document.getElementById('log-text');
+
+    const config = {
+        projectName: document.getElementById('projectName').value,
+        targetPath: document.getElementById('targetPath').value,
+        dbType: document.getElementById('dbType').value,
+        dbServer: document.getElementById('dbServer').value,
+        dbUser: document.getElementById('dbUser').value,
+        dbPw: document.getElementById('dbPw').value,
+        dbName: document.getElementById('dbName').value,
+        modules: selectedModules,
+        gormMode: true,
+    };
+    if (config.dbType === 'sqlite') {
+        const file = prompt('SQLite DB 파일 경로 (상대 경로는 대상 폴더 기준):', config.projectName + '.db');
+        if (!file) return;
+        config.dbName = file;
+    }
+
+    logOutput.classList.remove('hidden');
+    logText.textContent = 'DB 스키마를 읽는 중...';
+    try {
+        applyImport(await window.go.main.App.IntrospectDatabase(config), 'DB');
+    } catch (err) {
+        logText.textContent = '가져오기 실패: ' + err;
+    }
+}
+
+// CREATE TABLE 스크립트(.sql)에서 모델 가져오기 (방언은 스크립트에서 추정)
+async function importDDL(input) {
+    const file = input.files[0];
+    input.value = '';
+    if (!file) return;
+
+    const logText = document.getElementById('log-text');
+    document.getElementById('log-output').classList.remove('hidden');
+    logText.textContent = `${file.name} 분석 중...`;
+    try {
+        const script = await file.text();
+        applyImport(await window.go.main.App.ImportDDL(script, ''), file.name);
+    } catch (err) {
+        logText.textContent = '가져오기 실패: ' + err;
+    }
+}
+
+// OpenAPI 3 문서 또는 JSON Schema 파일(.yaml/.json)의 스키마에서 모델 가져오기
+async function importOpenAPI(input) {
+    const file = input.files[0];
+    input.value = '';
+    if (!file) return;
+
+    const logText = document.getElementById('log-text');
+    document.getElementById('log-output').classList.remove('hidden');
+    logText.textContent = `${file.name} 분석 중...`;
+    try {
+        const spec = await file.text();
+        applyImport(await window.go.main.App.ImportOpenAPI(spec, file.name.split('.')[0]), file.name);
+    } catch (err) {
+        logText.textContent = '가져오기 실패: ' + err;
+    }
+}
+
+// 가져온 모델로 현재 모델 목록을 교체
+function applyImport(result, source) {
+    const logText = document.getElementById('log-text');
+    const imported = (result && result.models) || [];
+    if (imported.length === 0) {
+        logText.textContent = '가져올 테이블이 없습니다' + formatWarnings(result && result.warnings);
+        return;
+    }
+    if (models.length > 0 && !confirm(`현재 모델 ${models.length}개를 ${source}에서 읽은 모델 ${imported.length}개로 바꾸시겠습니까?`)) {
+        logText.textContent = '가져오기가 취소되었습니다';
+        return;
+    }
+
+    models = imported.map(m => ({
+        ...m,
+        fields: m.fields.map(f => ({ ...f, gormTags: f.gormTags || [], defaultVal: f.defaultVal || '' })),
+    }));
+    activeModelIndex = 0;
+    renderModelTabs();
+    renderModelEditor();
+    renderRBACMatrix();
+    logText.textContent = `모델 ${imported.length}개를 가져왔습니다` + formatWarnings(result.warnings);
+}
+
+function formatWarnings(warnings) {
+    if (!warnings || warnings.length === 0) return '';
+    return '\n\n경고:\n- ' + warnings.join('\n- ');
+}
+
 function removeModel(index) {
     if (!confirm(`"${models[index].name}" 모델을 삭제하시겠습니까?`)) return;
     models.splice(index, 1);
@@ -210,6 +329,23 @@ function renderModelEditor() {
             </div>
             <button onclick="removeModel(${activeModelIndex})" class="btn btn-error btn-xs">삭제</button>
         </div>
+        <div class="flex flex-wrap gap-3 mb-3">
+            ${MODEL_OPTIONS.map(opt => `
+                <label class="label cursor-pointer gap-1 p-0">
+                    <input type="checkbox" class="checkbox checkbox-xs"
+                        ${model[opt.key] ? 'checked' : ''}
+                        onchange="toggleModelOption(${activeModelIndex}, '${opt.key}', this.checked)" />
+                    <span class="text-xs" title="${opt.help}">${opt.label}</span>
+                </label>
+            `).join('')}
+        </div>
+        <div class="flex gap-2 mb-3">
+            ${SCOPE_FIELDS.map(opt => `
+                <input type="text" class="input input-bordered input-xs w-32" value="${model[opt.key] || ''}"
+                    placeholder="${opt.label}: ${opt.placeholder}" title="${opt.help}"
+                    onchange="setModelOption(${activeModelIndex}, '${opt.key}', this.value.trim())" />
+            `).join('')}
+        </div>
         <table class="table table-xs">
             <thead>
                 <tr>
@@ -229,6 +365,9 @@ function renderModelEditor() {
                 <select onchange="updateField(${activeModelIndex}, ${fi}, 'type', this.value)" class="select select-bordered select-xs">
                     ${FIELD_TYPES.map(t => `<option value="${t.value}" ${t.value === field.type ? 'selected' : ''}>${t.label}</option>`).join('')}
                 </select>
+                ${field.type === 'enum' ? `
+                <input type="text" value="${(field.enumValues || []).join(', ')}" placeholder="draft, sent, paid"
+                    onchange="updateEnumValues(${activeModelIndex}, ${fi}, this.value)" class="input input-bordered input-xs w-32 mt-1" />` : ''}
             </td>
             <td>
                 <div class="flex flex-wrap gap-1">
@@ -254,6 +393,14 @@ function renderModelEditor() {
     editor.innerHTML = html;
 }
 
+function toggleModelOption(mi, key, checked) {
+    models[mi][key] = checked;
+}
+
+function setModelOption(mi, key, value) {
+    models[mi][key] = value;
+}
+
 function updateModelName(mi, name) {
     models[mi].name = name;
     renderModelTabs();
@@ -281,6 +428,13 @@ function updateField(mi, fi, key, value) {
     if (key === 'name') {
         models[mi].fields[fi].jsonName = toSnakeCase(value);
     }
+    if (key === 'type') {
+        renderModelEditor();
+    }
+}
+
+function updateEnumValues(mi, fi, value) {
+    models[mi].fields[fi].enumValues = value.split(',').map(v => v.trim()).filter(Boolean);
 }
 
 function toggleFieldTag(mi, fi, tag) {
@@ -370,14 +524,24 @@ async function generateProject() {
 
     if (gormMode) {
         config.dbType = document.getElementById('dbType').value;
+        config.autoMigrate = document.getElementById('autoMigrate').checked;
         config.models = models.map(m => ({
             name: m.name,
+            tableName: m.tableName || undefined,
+            timestamps: m.timestamps || undefined,
+            softDelete: m.softDelete || undefined,
+            audit: m.audit || undefined,
+            ownerField: m.ownerField || undefined,
+            tenantField: m.tenantField || undefined,
+            relations: m.relations,
             fields: m.fields.map(f => ({
                 name: f.name,
                 type: f.type,
                 gormTags: f.gormTags,
                 defaultVal: f.defaultVal || '',
                 jsonName: f.jsonName || toSnakeCase(f.name),
+                enumValues: f.type === 'enum' ? (f.enumValues || []) : undefined,
+                validation: f.validation,
             }))
         }));
 
@@ -402,7 +566,29 @@ async function generateProject() {
                 roles: roles,
                 jwtSecret: document.getElementById('jwtSecret').value,
                 modelPerms: modelPerms,
+                adminRoles: document.getElementById('rbacAdminRoles').value
+                    .split(',').map(r => r.trim()).filter(Boolean),
+                defaultRole: document.getElementById('rbacDefaultRole').value.trim(),
+                rateLimit: {
+                    auth: parseInt(document.getElementById('rateLimitAuth').value, 10) || 0,
+                    dashboard: parseInt(document.getElementById('rateLimitDashboard').value, 10) || 0,
+                    models: parseInt(document.getElementById('rateLimitModels').value, 10) || 0,
+                },
+                twoFactor: document.getElementById('rbacTwoFactor').checked,
+            };
+
+            const driver = document.getElementById('mailDriver').value;
+            config.mail = {
+                driver: driver,
+                from: document.getElementById('mailFrom').value.trim(),
+                baseURL: document.getElementById('mailBaseURL').value.trim(),
             };
+            if (driver === 'smtp') {
+                config.mail.smtp = {
+                    host: document.getElementById('smtpHost').value.trim(),
+                    port: parseInt(document.getElementById('smtpPort').value, 10) || 0,
+                };
+            }
         }
 
         logText.textContent = '[GORM] 생성 중... 잠시만 기다려주세요';
@@ -413,11 +599,27 @@ async function generateProject() {
     const lang = gormMode ? 'go' : currentLanguage;
 
     try {
-        const result = await window.go.main.App.GenerateProject(config, lang);
+        // 이전 생성 이후의 스키마 변경 중 데이터 손실 가능 항목 확인
+        let allowDestructive = false;
+        if (gormMode) {
+            const diff = await window.go.main.App.DiffProjectSchema(config);
+            if (diff && diff.destructive && diff.destructive.length > 0) {
+                const ok = confirm('다음 스키마 변경은 데이터가 손실될 수 있습니다:\n\n- ' +
+                    diff.destructive.join('\n- ') + '\n\n계속하시겠습니까?');
+                if (!ok) {
+                    logText.textContent = '생성이 취소되었습니다';
+                    return;
+                }
+                allowDestructive = true;
+            }
+        }
+
+        const result = await window.go.main.App.GenerateProject(config, lang, allowDestructive);
 
         if (result.success) {
             if (gormMode) {
-                logText.textContent = 'GORM Full-Stack 프로젝트 생성 완료!\n터미널에서 실행:\n  cd ' + config.targetPath + '\n  go mod tidy\n  go run .';
+                logText.textContent = 'GORM Full-Stack 프로젝트 생성 완료!\n터미널에서 실행:\n  cd ' + config.targetPath + '\n  go mod tidy' +
+                    (config.autoMigrate ? '' : '\n  go run . migrate up') + '\n  go run .';
             } else if (currentLanguage === 'go') {
                 logText.textContent = 'Go 서버 생성 완료!\n터미널에서 실행:\n  go mod tidy\n  go run .';
             } else {
@@ -9,16 +9,28 @@ export function CreateBuilderProject(arg1:string):Promise<builder.BuilderProject
 
 export function DeleteComponent(arg1:string,arg2:string):Promise<void>;
 
+export function DiffProjectSchema(arg1:domain.ProjectConfig):Promise<domain.SchemaDiff>;
+
 export function ExportBuilderHTML():Promise<void>;
 
-export function GenerateProject(arg1:domain.ProjectConfig,arg2:string):Promise<Record<string, any>>;
+export function GenerateProject(arg1:domain.ProjectConfig,arg2:string,arg3:boolean):Promise<Record<string, any>>;
 
 export function GetCurrentProject():Promise<builder.BuilderProject>;
 
 export function GetModules():Promise<Array<domain.ModuleDef>>;
 
+export function ImportDDL(arg1:string,arg2:string):Promise<domain.ModelImport>;
+
+export function ImportOpenAPI(arg1:string,arg2:string):Promise<domain.ModelImport>;
+
+export function IntrospectDatabase(arg1:domain.ProjectConfig):Promise<domain.ModelImport>;
+
 export function LoadBuilderProject():Promise<builder.BuilderProject>;
 
+export function PlanProject(arg1:domain.ProjectConfig,arg2:string):Promise<domain.GenerationPlan>;
+
+export function RegenerateProject(arg1:domain.ProjectConfig,arg2:string,arg3:boolean):Promise<Record<string, any>>;
+
 export function ReorderComponents(arg1:string,arg2:Array<string>):Promise<void>;
 
 export function SaveBuilderProject():Promise<void>;
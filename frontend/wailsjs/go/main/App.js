@@ -14,12 +14,16 @@ export function DeleteComponent(arg1, arg2) {
   return window['go']['main']['App']['DeleteComponent'](arg1, arg2);
 }
 
+export function DiffProjectSchema(arg1) {
+  return window['go']['main']['App']['DiffProjectSchema'](arg1);
+}
+
 export function ExportBuilderHTML() {
   return window['go']['main']['App']['ExportBuilderHTML']();
 }
 
-export function GenerateProject(arg1, arg2) {
-  return window['go']['main']['App']['GenerateProject'](arg1, arg2);
+export function GenerateProject(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GenerateProject'](arg1, arg2, arg3);
 }
 
 export function GetCurrentProject() {
@@ -30,10 +34,30 @@ export function GetModules() {
   return window['go']['main']['App']['GetModules']();
 }
 
+export function ImportDDL(arg1, arg2) {
+  return window['go']['main']['App']['ImportDDL'](arg1, arg2);
+}
+
+export function ImportOpenAPI(arg1, arg2) {
+  return window['go']['main']['App']['ImportOpenAPI'](arg1, arg2);
+}
+
+export function IntrospectDatabase(arg1) {
+  return window['go']['main']['App']['IntrospectDatabase'](arg1);
+}
+
 export function LoadBuilderProject() {
   return window['go']['main']['App']['LoadBuilderProject']();
 }
 
+export function PlanProject(arg1, arg2) {
+  return window['go']['main']['App']['PlanProject'](arg1, arg2);
+}
+
+export function RegenerateProject(arg1, arg2, arg3) {
+  return window['go']['main']['App']['RegenerateProject'](arg1, arg2, arg3);
+}
+
 export function ReorderComponents(arg1, arg2) {
   return window['go']['main']['App']['ReorderComponents'](arg1, arg2);
 }
@@ -130,12 +130,98 @@ export namespace domain {
 	        this.content = source["content"];
 	    }
 	}
+	export class PlannedFile {
+	    path: string;
+	    size: number;
+	    sha256: string;
+	    action: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PlannedFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.sha256 = source["sha256"];
+	        this.action = source["action"];
+	    }
+	}
+	export class GenerationPlan {
+	    targetDir: string;
+	    targetExists: boolean;
+	    merge: boolean;
+	    files: PlannedFile[];
+	    deletes: PlannedFile[];
+	    warnings: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new GenerationPlan(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.targetDir = source["targetDir"];
+	        this.targetExists = source["targetExists"];
+	        this.merge = source["merge"];
+	        this.files = this.convertValues(source["files"], PlannedFile);
+	        this.deletes = this.convertValues(source["deletes"], PlannedFile);
+	        this.warnings = source["warnings"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FieldValidation {
+	    required?: boolean;
+	    min?: number;
+	    max?: number;
+	    minLength?: number;
+	    maxLength?: number;
+	    pattern?: string;
+	    email?: boolean;
+	    unique?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FieldValidation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.required = source["required"];
+	        this.min = source["min"];
+	        this.max = source["max"];
+	        this.minLength = source["minLength"];
+	        this.maxLength = source["maxLength"];
+	        this.pattern = source["pattern"];
+	        this.email = source["email"];
+	        this.unique = source["unique"];
+	    }
+	}
 	export class FieldDef {
 	    name: string;
 	    type: string;
 	    gormTags: string[];
 	    defaultVal: string;
 	    jsonName: string;
+	    enumValues?: string[];
+	    validation?: FieldValidation;
 	
 	    static createFrom(source: any = {}) {
 	        return new FieldDef(source);
@@ -148,11 +234,62 @@ export namespace domain {
 	        this.gormTags = source["gormTags"];
 	        this.defaultVal = source["defaultVal"];
 	        this.jsonName = source["jsonName"];
+	        this.enumValues = source["enumValues"];
+	        this.validation = this.convertValues(source["validation"], FieldValidation);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RelationDef {
+	    name: string;
+	    type: string;
+	    model: string;
+	    foreignKey?: string;
+	    joinTable?: string;
+	    displayField?: string;
+	    optional?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new RelationDef(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.model = source["model"];
+	        this.foreignKey = source["foreignKey"];
+	        this.joinTable = source["joinTable"];
+	        this.displayField = source["displayField"];
+	        this.optional = source["optional"];
 	    }
 	}
 	export class ModelDef {
 	    name: string;
 	    fields: FieldDef[];
+	    relations?: RelationDef[];
+	    tableName?: string;
+	    timestamps?: boolean;
+	    softDelete?: boolean;
+	    audit?: boolean;
+	    ownerField?: string;
+	    tenantField?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new ModelDef(source);
@@ -162,6 +299,13 @@ export namespace domain {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.name = source["name"];
 	        this.fields = this.convertValues(source["fields"], FieldDef);
+	        this.relations = this.convertValues(source["relations"], RelationDef);
+	        this.tableName = source["tableName"];
+	        this.timestamps = source["timestamps"];
+	        this.softDelete = source["softDelete"];
+	        this.audit = source["audit"];
+	        this.ownerField = source["ownerField"];
+	        this.tenantField = source["tenantField"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -274,11 +418,31 @@ export namespace domain {
 		    return a;
 		}
 	}
+	export class RateLimitConfig {
+	    auth?: number;
+	    dashboard?: number;
+	    models?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RateLimitConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.auth = source["auth"];
+	        this.dashboard = source["dashboard"];
+	        this.models = source["models"];
+	    }
+	}
 	export class RBACConfig {
 	    enabled: boolean;
 	    roles: string[];
 	    jwtSecret: string;
 	    modelPerms: ModelRBAC[];
+	    adminRoles?: string[];
+	    defaultRole?: string;
+	    rateLimit?: RateLimitConfig;
+	    twoFactor?: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new RBACConfig(source);
@@ -290,6 +454,120 @@ export namespace domain {
 	        this.roles = source["roles"];
 	        this.jwtSecret = source["jwtSecret"];
 	        this.modelPerms = this.convertValues(source["modelPerms"], ModelRBAC);
+	        this.adminRoles = source["adminRoles"];
+	        this.defaultRole = source["defaultRole"];
+	        this.rateLimit = this.convertValues(source["rateLimit"], RateLimitConfig);
+	        this.twoFactor = source["twoFactor"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class S3Config {
+	    endpoint: string;
+	    region?: string;
+	    bucket: string;
+	    useSSL?: boolean;
+	    pathStyle?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new S3Config(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.endpoint = source["endpoint"];
+	        this.region = source["region"];
+	        this.bucket = source["bucket"];
+	        this.useSSL = source["useSSL"];
+	        this.pathStyle = source["pathStyle"];
+	    }
+	}
+	export class StorageConfig {
+	    driver?: string;
+	    root?: string;
+	    maxSizeMB?: number;
+	    allowedTypes?: string[];
+	    s3?: S3Config;
+	
+	    static createFrom(source: any = {}) {
+	        return new StorageConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.driver = source["driver"];
+	        this.root = source["root"];
+	        this.maxSizeMB = source["maxSizeMB"];
+	        this.allowedTypes = source["allowedTypes"];
+	        this.s3 = this.convertValues(source["s3"], S3Config);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SMTPConfig {
+	    host: string;
+	    port?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SMTPConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.host = source["host"];
+	        this.port = source["port"];
+	    }
+	}
+	export class MailConfig {
+	    driver?: string;
+	    from?: string;
+	    baseURL?: string;
+	    dir?: string;
+	    smtp?: SMTPConfig;
+	
+	    static createFrom(source: any = {}) {
+	        return new MailConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.driver = source["driver"];
+	        this.from = source["from"];
+	        this.baseURL = source["baseURL"];
+	        this.dir = source["dir"];
+	        this.smtp = this.convertValues(source["smtp"], SMTPConfig);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -323,6 +601,9 @@ export namespace domain {
 	    models?: ModelDef[];
 	    dbType?: string;
 	    rbac?: RBACConfig;
+	    autoMigrate?: boolean;
+	    storage?: StorageConfig;
+	    mail?: MailConfig;
 	
 	    static createFrom(source: any = {}) {
 	        return new ProjectConfig(source);
@@ -342,6 +623,265 @@ export namespace domain {
 	        this.models = this.convertValues(source["models"], ModelDef);
 	        this.dbType = source["dbType"];
 	        this.rbac = this.convertValues(source["rbac"], RBACConfig);
+	        this.autoMigrate = source["autoMigrate"];
+	        this.storage = this.convertValues(source["storage"], StorageConfig);
+	        this.mail = this.convertValues(source["mail"], MailConfig);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SQLColumn {
+	    name: string;
+	    field?: string;
+	    kind: string;
+	    size?: number;
+	    rawType?: string;
+	    primaryKey?: boolean;
+	    autoIncrement?: boolean;
+	    notNull?: boolean;
+	    unique?: boolean;
+	    default?: string;
+	    enumValues?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SQLColumn(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.field = source["field"];
+	        this.kind = source["kind"];
+	        this.size = source["size"];
+	        this.rawType = source["rawType"];
+	        this.primaryKey = source["primaryKey"];
+	        this.autoIncrement = source["autoIncrement"];
+	        this.notNull = source["notNull"];
+	        this.unique = source["unique"];
+	        this.default = source["default"];
+	        this.enumValues = source["enumValues"];
+	    }
+	}
+	export class SQLForeignKey {
+	    name: string;
+	    column: string;
+	    refTable: string;
+	    refColumn: string;
+	    onDelete?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SQLForeignKey(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.column = source["column"];
+	        this.refTable = source["refTable"];
+	        this.refColumn = source["refColumn"];
+	        this.onDelete = source["onDelete"];
+	    }
+	}
+	export class SQLIndex {
+	    name: string;
+	    columns: string[];
+	    unique?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SQLIndex(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.columns = source["columns"];
+	        this.unique = source["unique"];
+	    }
+	}
+	export class SQLTable {
+	    name: string;
+	    model?: string;
+	    columns: SQLColumn[];
+	    primaryKey?: string[];
+	    indexes?: SQLIndex[];
+	    foreignKeys?: SQLForeignKey[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SQLTable(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.model = source["model"];
+	        this.columns = this.convertValues(source["columns"], SQLColumn);
+	        this.primaryKey = source["primaryKey"];
+	        this.indexes = this.convertValues(source["indexes"], SQLIndex);
+	        this.foreignKeys = this.convertValues(source["foreignKeys"], SQLForeignKey);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ColumnChange {
+	    old: SQLColumn;
+	    new: SQLColumn;
+	    narrowing?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ColumnChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.old = this.convertValues(source["old"], SQLColumn);
+	        this.new = this.convertValues(source["new"], SQLColumn);
+	        this.narrowing = source["narrowing"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TableDiff {
+	    table: string;
+	    model?: string;
+	    addedColumns?: SQLColumn[];
+	    removedColumns?: SQLColumn[];
+	    changedColumns?: ColumnChange[];
+	    addedIndexes?: SQLIndex[];
+	    removedIndexes?: SQLIndex[];
+	    addedForeignKeys?: SQLForeignKey[];
+	    removedForeignKeys?: SQLForeignKey[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TableDiff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.table = source["table"];
+	        this.model = source["model"];
+	        this.addedColumns = this.convertValues(source["addedColumns"], SQLColumn);
+	        this.removedColumns = this.convertValues(source["removedColumns"], SQLColumn);
+	        this.changedColumns = this.convertValues(source["changedColumns"], ColumnChange);
+	        this.addedIndexes = this.convertValues(source["addedIndexes"], SQLIndex);
+	        this.removedIndexes = this.convertValues(source["removedIndexes"], SQLIndex);
+	        this.addedForeignKeys = this.convertValues(source["addedForeignKeys"], SQLForeignKey);
+	        this.removedForeignKeys = this.convertValues(source["removedForeignKeys"], SQLForeignKey);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SchemaDiff {
+	    addedTables?: SQLTable[];
+	    removedTables?: SQLTable[];
+	    changedTables?: TableDiff[];
+	    destructive?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SchemaDiff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.addedTables = this.convertValues(source["addedTables"], SQLTable);
+	        this.removedTables = this.convertValues(source["removedTables"], SQLTable);
+	        this.changedTables = this.convertValues(source["changedTables"], TableDiff);
+	        this.destructive = source["destructive"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ModelImport {
+	    models: ModelDef[];
+	    warnings?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelImport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.models = this.convertValues(source["models"], ModelDef);
+	        this.warnings = source["warnings"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -0,0 +1,83 @@
+package application
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"ggami-go/internal/domain"
+)
+
+// LoadProjectConfig reads a ProjectConfig from a JSON or YAML file.
+// YAML files use the same keys as the JSON form (projectName, gormMode, ...).
+func LoadProjectConfig(path string) (domain.ProjectConfig, error) {
+	var config domain.ProjectConfig
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("read config: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		// Decode generically, then round-trip through JSON so the
+		// json struct tags on domain types stay the single source of truth
+		var raw interface{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return config, fmt.Errorf("parse yaml config: %w", err)
+		}
+		data, err = json.Marshal(raw)
+		if err != nil {
+			return config, fmt.Errorf("convert yaml config: %w", err)
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&config); err != nil {
+		return config, fmt.Errorf("parse config: %w", err)
+	}
+	return config, nil
+}
+
+// MarshalProjectConfig encodes a ProjectConfig as YAML, or as indented JSON
+// when format is "json". Keys are the JSON keys, as LoadProjectConfig
+// expects; YAML leaves out empty values.
+func MarshalProjectConfig(config domain.ProjectConfig, format string) ([]byte, error) {
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if format == "json" {
+		return append(data, '\n'), nil
+	}
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(pruneEmpty(raw))
+}
+
+// pruneEmpty drops null and "" values so YAML output stays readable
+func pruneEmpty(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if value == nil || value == "" {
+				delete(v, key)
+				continue
+			}
+			v[key] = pruneEmpty(value)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = pruneEmpty(v[i])
+		}
+	}
+	return v
+}
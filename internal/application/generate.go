@@ -1,21 +1,80 @@
 package application
 
-import "ggami-go/internal/domain"
+import (
+	"fmt"
+
+	"ggami-go/internal/domain"
+)
+
+// GenerateOptions tunes a single generation run
+type GenerateOptions struct {
+	Progress ProgressFunc // optional per-step progress callback
+	Merge    bool         // preserve user edits in an existing target (see FinalizeStep)
+
+	AllowDestructive bool // confirm schema changes that can lose data (see GenerateMigrationsStep)
+}
 
 // GenerateProject orchestrates the full project generation using the pipeline
 func GenerateProject(config domain.ProjectConfig, language string) (string, error) {
+	return GenerateProjectWithOptions(config, language, GenerateOptions{})
+}
+
+// GenerateProjectWithOptions runs the generation pipeline with the given options
+func GenerateProjectWithOptions(config domain.ProjectConfig, language string, opts GenerateOptions) (string, error) {
 	ctx := &domain.PipelineContext{
-		Config:   config,
-		Language: language,
-		FinalDir: config.TargetPath,
+		Config:           config,
+		Language:         language,
+		FinalDir:         config.TargetPath,
+		Merge:            opts.Merge,
+		AllowDestructive: opts.AllowDestructive,
 	}
 
 	steps := buildSteps(config, language)
-	if err := NewPipeline(steps...).Run(ctx); err != nil {
+	if err := NewPipeline(steps...).OnProgress(opts.Progress).Run(ctx); err != nil {
 		return "", err
 	}
 
-	return "Generation complete: " + config.TargetPath, nil
+	result := "Generation complete: " + config.TargetPath
+	if ctx.Plan != nil {
+		result += mergeSummary(ctx.Plan)
+	}
+	for _, w := range ctx.Warnings {
+		result += "\nwarning: " + w
+	}
+	return result, nil
+}
+
+// mergeSummary lists the user-modified files a merge run preserved
+func mergeSummary(plan *domain.GenerationPlan) string {
+	var kept, sidecars []string
+	for _, f := range plan.Files {
+		switch f.Action {
+		case domain.FileKeep:
+			kept = append(kept, f.Path)
+		case domain.FileSidecar:
+			sidecars = append(sidecars, f.Path)
+		}
+	}
+	if len(kept) == 0 && len(sidecars) == 0 {
+		return ""
+	}
+
+	summary := fmt.Sprintf(" (%d modified files kept, %d new versions written as %s)", len(kept), len(sidecars), domain.SidecarSuffix)
+	for _, p := range sidecars {
+		summary += "\n  " + p + domain.SidecarSuffix
+	}
+	return summary
+}
+
+// ValidateProject runs only the validation and module resolution steps,
+// without touching the filesystem
+func ValidateProject(config domain.ProjectConfig, language string, progress ProgressFunc) error {
+	ctx := &domain.PipelineContext{
+		Config:   config,
+		Language: language,
+		FinalDir: config.TargetPath,
+	}
+	return NewPipeline(&ValidateConfigStep{}, &ResolveModulesStep{}).OnProgress(progress).Run(ctx)
 }
 
 func buildSteps(config domain.ProjectConfig, language string) []PipelineStep {
@@ -38,6 +97,7 @@ func buildSteps(config domain.ProjectConfig, language string) []PipelineStep {
 			&GenerateModelsStep{},
 			&GenerateHandlersStep{},
 			&GenerateTemplatesStep{},
+			&GenerateMigrationsStep{},
 		)
 		if config.RBAC != nil && config.RBAC.Enabled {
 			steps = append(steps, &GenerateMiddlewareStep{})
@@ -51,7 +111,7 @@ func buildSteps(config domain.ProjectConfig, language string) []PipelineStep {
 		)
 	}
 
-	// Common: finalize
-	steps = append(steps, &FinalizeStep{})
+	// Common: keep protected regions, record manifest, finalize
+	steps = append(steps, &PreserveRegionsStep{}, &WriteManifestStep{}, &FinalizeStep{})
 	return steps
 }
@@ -0,0 +1,82 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"ggami-go/internal/domain"
+	"ggami-go/internal/introspect"
+)
+
+// introspectTimeout bounds connecting to and reading a database catalog
+const introspectTimeout = 30 * time.Second
+
+// IntrospectDatabase reads the schema of the database configured in config
+// and maps it to models. For SQLite, dbName is the database file (default
+// <projectName>.db, as generated projects use); relative paths are resolved
+// against targetPath when it is set. sslMode is the PostgreSQL sslmode, ""
+// for the driver's default.
+func IntrospectDatabase(config domain.ProjectConfig, sslMode string) (*domain.ModelImport, error) {
+	if config.DBType == "" || config.DBType == domain.DBTypeSQLite {
+		config.DBType = domain.DBTypeSQLite
+		if config.DBName == "" && config.ProjectName != "" {
+			config.DBName = config.ProjectName + ".db"
+		}
+		if config.DBName != "" && !filepath.IsAbs(config.DBName) && config.TargetPath != "" {
+			config.DBName = filepath.Join(config.TargetPath, config.DBName)
+		}
+	}
+	dsn, err := introspect.DSN(config, sslMode)
+	if err != nil {
+		return nil, err
+	}
+	return IntrospectDSN(config.DBType, dsn)
+}
+
+// IntrospectDSN reads the schema of the database at dsn and maps it to models
+func IntrospectDSN(dbType domain.DBType, dsn string) (*domain.ModelImport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), introspectTimeout)
+	defer cancel()
+	return introspect.Import(ctx, dbType, dsn)
+}
+
+// ImportDDL maps the tables created by a DDL script to models. dbType may be
+// empty to guess the dialect; unsupported statements become warnings.
+func ImportDDL(script string, dbType domain.DBType) *domain.ModelImport {
+	return introspect.ImportDDL(script, dbType)
+}
+
+// ImportDDLFile reads a DDL script and maps its tables to models. Returns
+// the dialect used, which is guessed when dbType is empty.
+func ImportDDLFile(path string, dbType domain.DBType) (*domain.ModelImport, domain.DBType, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("read ddl: %w", err)
+	}
+	if dbType == "" {
+		dbType = introspect.GuessDialect(string(data))
+	}
+	return ImportDDL(string(data), dbType), dbType, nil
+}
+
+// ImportOpenAPI maps the object schemas of an OpenAPI 3 document or JSON
+// Schema file (YAML or JSON) to models. name titles a root schema that has
+// none, typically the file name.
+func ImportOpenAPI(document, name string) (*domain.ModelImport, error) {
+	return introspect.ImportOpenAPI([]byte(document), name)
+}
+
+// ImportOpenAPIFile reads an OpenAPI 3 document or JSON Schema file and maps
+// its object schemas to models
+func ImportOpenAPIFile(path string) (*domain.ModelImport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read schema: %w", err)
+	}
+	name, _, _ := strings.Cut(filepath.Base(path), ".") // person.schema.json → person
+	return ImportOpenAPI(string(data), name)
+}
@@ -0,0 +1,85 @@
+package application
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"ggami-go/internal/domain"
+	"ggami-go/internal/generator"
+)
+
+// DiffProjectSchema compares config with the config recorded in the manifest
+// of its target directory. Returns nil if the target was never generated
+// with a recorded config.
+func DiffProjectSchema(config domain.ProjectConfig) (*domain.SchemaDiff, error) {
+	previous, err := previousConfig(config.TargetPath)
+	if err != nil || previous == nil {
+		return nil, err
+	}
+	diff := generator.DiffConfigs(*previous, config)
+	return &diff, nil
+}
+
+// previousConfig returns the config the target was last generated with
+func previousConfig(dir string) (*domain.ProjectConfig, error) {
+	manifest, err := readManifest(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	if manifest == nil {
+		return nil, nil
+	}
+	return manifest.Config, nil
+}
+
+// manifestConfig is the config recorded in the manifest; secrets stay out
+func manifestConfig(config domain.ProjectConfig) *domain.ProjectConfig {
+	config.TargetPath = ""
+	config.DBPw = ""
+	if config.RBAC != nil {
+		rbac := *config.RBAC
+		rbac.JWTSecret = ""
+		config.RBAC = &rbac
+	}
+	return &config
+}
+
+// copyMigrationHistory copies the SQL migrations already in finalDir into
+// root so regeneration never rewrites applied migrations. Returns the
+// highest version found (0 if there are none).
+func copyMigrationHistory(out domain.OutputFS, finalDir, root string) (int, error) {
+	paths, err := filepath.Glob(filepath.Join(finalDir, "migrations", "*", "*.sql"))
+	if err != nil {
+		return 0, err
+	}
+
+	latest := 0
+	for _, path := range paths {
+		rel, err := filepath.Rel(finalDir, path)
+		if err != nil {
+			return 0, err
+		}
+		prefix, _, _ := strings.Cut(filepath.Base(path), "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			continue // not a generated migration
+		}
+		latest = max(latest, version)
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return 0, err
+		}
+		target := filepath.Join(root, rel)
+		if err := out.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return 0, err
+		}
+		if err := out.WriteFile(target, data, 0644); err != nil {
+			return 0, err
+		}
+	}
+	return latest, nil
+}
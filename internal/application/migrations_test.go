@@ -0,0 +1,91 @@
+package application
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"ggami-go/internal/domain"
+)
+
+// shopConfig is a GORM project in target with a Product model holding the
+// given fields
+func shopConfig(target string, fields ...domain.FieldDef) domain.ProjectConfig {
+	return domain.ProjectConfig{
+		ProjectName: "shop",
+		TargetPath:  target,
+		GormMode:    true,
+		DBType:      domain.DBTypeSQLite,
+		Models: []domain.ModelDef{{Name: "Product", Fields: append([]domain.FieldDef{
+			{Name: "ID", Type: "uint", GormTags: []string{"primaryKey"}},
+		}, fields...)}},
+	}
+}
+
+// migrationFiles lists the migration files under dir as dialect/name
+func migrationFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join(dir, "migrations", "*", "*.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, p := range paths {
+		rel, err := filepath.Rel(filepath.Join(dir, "migrations"), p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, filepath.ToSlash(rel))
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestDestructiveChangesNeedConfirmation(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "shop")
+	name := domain.FieldDef{Name: "Name", Type: "string"}
+	code := domain.FieldDef{Name: "Code", Type: "int"}
+	if _, err := GenerateProject(shopConfig(target, name, code), "go"); err != nil {
+		t.Fatal(err)
+	}
+	before := migrationFiles(t, target)
+	if len(before) == 0 {
+		t.Fatal("no migrations generated")
+	}
+
+	dropped := shopConfig(target, name)
+	const change = "drop column products.code and its values"
+	_, err := GenerateProjectWithOptions(dropped, "go", GenerateOptions{Merge: true})
+	if err == nil || !strings.Contains(err.Error(), change) {
+		t.Fatalf("error %v, want the run refused with %q", err, change)
+	}
+	if after := migrationFiles(t, target); !slices.Equal(after, before) {
+		t.Errorf("refused run changed the migrations: %q, want %q", after, before)
+	}
+	if previous, err := previousConfig(target); err != nil || len(previous.Models[0].Fields) != 3 {
+		t.Errorf("refused run changed the manifest config: %+v, %v", previous, err)
+	}
+
+	// a dry run reports the change instead of failing
+	plan, err := PlanProject(dropped, "go", GenerateOptions{Merge: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "destructive schema change needs confirmation: " + change; !slices.Contains(plan.Warnings, want) {
+		t.Errorf("plan warnings %q, want %q", plan.Warnings, want)
+	}
+
+	if _, err := GenerateProjectWithOptions(dropped, "go", GenerateOptions{Merge: true, AllowDestructive: true}); err != nil {
+		t.Fatalf("confirmed run: %v", err)
+	}
+	after := migrationFiles(t, target)
+	if want := "sqlite/0002_drop_products_code.up.sql"; !slices.Contains(after, want) {
+		t.Errorf("confirmed run wrote %q, want %s", after, want)
+	}
+	for _, name := range before {
+		if !slices.Contains(after, name) {
+			t.Errorf("migration %s was removed", name)
+		}
+	}
+}
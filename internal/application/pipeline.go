@@ -13,10 +13,51 @@ type PipelineStep interface {
 	Rollback(ctx *domain.PipelineContext) error
 }
 
+// StepStatus describes where a step is in its lifecycle
+type StepStatus string
+
+const (
+	StepStarted    StepStatus = "started"
+	StepCompleted  StepStatus = "completed"
+	StepFailed     StepStatus = "failed"
+	StepRolledBack StepStatus = "rolled-back"
+)
+
+// StepEvent is reported to a ProgressFunc whenever a step changes status
+type StepEvent struct {
+	Step   string
+	Index  int // 1-based position of the step in the pipeline
+	Total  int
+	Status StepStatus
+	Err    error // set for StepFailed and failed rollbacks
+}
+
+// ProgressFunc receives step events while a pipeline runs
+type ProgressFunc func(StepEvent)
+
+// StepError reports which step of the pipeline failed
+type StepError struct {
+	Step        string
+	Err         error
+	RollbackOf  string // step whose rollback also failed, if any
+	RollbackErr error
+}
+
+func (e *StepError) Error() string {
+	if e.RollbackErr != nil {
+		return fmt.Sprintf("step %q failed: %v (rollback of %q also failed: %v)",
+			e.Step, e.Err, e.RollbackOf, e.RollbackErr)
+	}
+	return fmt.Sprintf("step %q failed: %v", e.Step, e.Err)
+}
+
+func (e *StepError) Unwrap() error { return e.Err }
+
 // Pipeline runs steps sequentially with rollback on failure
 type Pipeline struct {
 	steps     []PipelineStep
 	completed []PipelineStep
+	progress  ProgressFunc
 }
 
 // NewPipeline creates a pipeline with the given steps
@@ -24,20 +65,38 @@ func NewPipeline(steps ...PipelineStep) *Pipeline {
 	return &Pipeline{steps: steps}
 }
 
+// OnProgress registers a callback that is notified as steps start, finish or fail
+func (p *Pipeline) OnProgress(fn ProgressFunc) *Pipeline {
+	p.progress = fn
+	return p
+}
+
 // Run executes all steps. On failure, rolls back completed steps in reverse order.
 func (p *Pipeline) Run(ctx *domain.PipelineContext) error {
-	for _, step := range p.steps {
+	total := len(p.steps)
+	for i, step := range p.steps {
+		p.emit(StepEvent{Step: step.Name(), Index: i + 1, Total: total, Status: StepStarted})
 		if err := step.Execute(ctx); err != nil {
+			p.emit(StepEvent{Step: step.Name(), Index: i + 1, Total: total, Status: StepFailed, Err: err})
 			// Rollback completed steps in reverse
-			for i := len(p.completed) - 1; i >= 0; i-- {
-				if rbErr := p.completed[i].Rollback(ctx); rbErr != nil {
-					return fmt.Errorf("step %q failed: %w (rollback of %q also failed: %v)",
-						step.Name(), err, p.completed[i].Name(), rbErr)
+			for j := len(p.completed) - 1; j >= 0; j-- {
+				done := p.completed[j]
+				if rbErr := done.Rollback(ctx); rbErr != nil {
+					p.emit(StepEvent{Step: done.Name(), Index: j + 1, Total: total, Status: StepFailed, Err: rbErr})
+					return &StepError{Step: step.Name(), Err: err, RollbackOf: done.Name(), RollbackErr: rbErr}
 				}
+				p.emit(StepEvent{Step: done.Name(), Index: j + 1, Total: total, Status: StepRolledBack})
 			}
-			return fmt.Errorf("step %q failed: %w", step.Name(), err)
+			return &StepError{Step: step.Name(), Err: err}
 		}
 		p.completed = append(p.completed, step)
+		p.emit(StepEvent{Step: step.Name(), Index: i + 1, Total: total, Status: StepCompleted})
 	}
 	return nil
 }
+
+func (p *Pipeline) emit(ev StepEvent) {
+	if p.progress != nil {
+		p.progress(ev)
+	}
+}
@@ -0,0 +1,227 @@
+package application
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"ggami-go/internal/domain"
+	"ggami-go/internal/generator"
+)
+
+// PlanProject runs the generation pipeline in dry-run mode. Every step
+// renders into memory and nothing under config.TargetPath is touched; the
+// returned plan lists the files that would be created, overwritten or deleted.
+func PlanProject(config domain.ProjectConfig, language string, opts GenerateOptions) (*domain.GenerationPlan, error) {
+	ctx := &domain.PipelineContext{
+		Config:           config,
+		Language:         language,
+		FinalDir:         config.TargetPath,
+		DryRun:           true,
+		Merge:            opts.Merge,
+		AllowDestructive: opts.AllowDestructive,
+	}
+
+	steps := buildSteps(config, language)
+	if err := NewPipeline(steps...).OnProgress(opts.Progress).Run(ctx); err != nil {
+		return nil, err
+	}
+	ctx.Plan.Warnings = ctx.Warnings
+	return ctx.Plan, nil
+}
+
+// buildPlan compares the files rendered under root with finalDir on disk.
+// Without merge, Finalize replaces the whole target; with merge, files the
+// user modified since the last generation (per the manifest) are preserved.
+func buildPlan(out domain.OutputFS, root, finalDir string, merge bool) (*domain.GenerationPlan, error) {
+	plan := &domain.GenerationPlan{TargetDir: finalDir, Merge: merge}
+
+	existing, stripped, err := hashTree(finalDir)
+	if err != nil {
+		return nil, err
+	}
+	plan.TargetExists = existing != nil
+
+	var previous map[string]string
+	if merge {
+		manifest, err := readManifest(finalDir)
+		if err != nil {
+			return nil, err
+		}
+		if manifest != nil {
+			previous = manifest.Files
+		}
+	}
+
+	generated := make(map[string]bool)
+	err = out.Walk(root, func(path string, data []byte) error {
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		generated[rel] = true
+
+		entry := domain.PlannedFile{
+			Path:   rel,
+			Size:   int64(len(data)),
+			SHA256: hashBytes(data),
+		}
+		cur, exists := existing[rel]
+		switch {
+		case !exists:
+			entry.Action = domain.FileCreate
+		case cur.SHA256 == entry.SHA256:
+			entry.Action = domain.FileUnchanged
+		case !merge || rel == domain.ManifestPath || previous[rel] == stripped[rel]:
+			// Replacing the whole target, or the user never touched this file
+			// outside its protected regions, which data already carries over
+			entry.Action = domain.FileOverwrite
+		case previous[rel] == generatedHash(data):
+			// User edited it and the generator output did not change
+			entry.Action = domain.FileKeep
+		default:
+			entry.Action = domain.FileSidecar
+		}
+		plan.Files = append(plan.Files, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for rel, cur := range existing {
+		if generated[rel] {
+			continue
+		}
+		if merge {
+			// Only stale generated files the user left untouched are removed;
+			// user-only and user-modified files stay, and so do files with
+			// code in their protected regions
+			if gen, ok := previous[rel]; !ok || gen != stripped[rel] || gen != cur.SHA256 {
+				continue
+			}
+		}
+		cur.Action = domain.FileDelete
+		plan.Deletes = append(plan.Deletes, cur)
+	}
+	sort.Slice(plan.Deletes, func(i, j int) bool { return plan.Deletes[i].Path < plan.Deletes[j].Path })
+
+	return plan, nil
+}
+
+// applyMergePlan writes a merge plan into finalDir, reading generated
+// content from root in out
+func applyMergePlan(plan *domain.GenerationPlan, out domain.OutputFS, root, finalDir string) error {
+	for _, f := range plan.Files {
+		target := filepath.Join(finalDir, filepath.FromSlash(f.Path))
+		switch f.Action {
+		case domain.FileCreate, domain.FileOverwrite:
+		case domain.FileSidecar:
+			target += domain.SidecarSuffix
+		default:
+			continue
+		}
+
+		data, err := out.ReadFile(filepath.Join(root, filepath.FromSlash(f.Path)))
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, data, 0644); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range plan.Deletes {
+		if err := os.Remove(filepath.Join(finalDir, filepath.FromSlash(f.Path))); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// readManifest loads the generation manifest from a target directory.
+// Returns nil if the target was never generated (or predates manifests).
+func readManifest(dir string) (*domain.GenerationManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(domain.ManifestPath)))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var manifest domain.GenerationManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// hashTree hashes every regular file under dir, keyed by slash-separated
+// relative path, and returns their generatedHash alongside. Returns nil if
+// dir does not exist.
+func hashTree(dir string) (map[string]domain.PlannedFile, map[string]string, error) {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil, nil, nil
+	}
+
+	files := make(map[string]domain.PlannedFile)
+	stripped := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		files[rel] = domain.PlannedFile{Path: rel, Size: int64(len(data)), SHA256: hashBytes(data)}
+		stripped[rel] = generatedHash(data)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return files, stripped, nil
+}
+
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// generatedHash hashes data with the bodies of its protected regions
+// blanked, so the manifest records only what the generator owns and edits
+// inside regions do not count as changes to the file
+func generatedHash(data []byte) string {
+	if !generator.HasRegions(data) {
+		return hashBytes(data)
+	}
+	regions, err := generator.ExtractRegions(data)
+	if err != nil {
+		return hashBytes(data)
+	}
+	for name := range regions {
+		regions[name] = ""
+	}
+	blanked, _, err := generator.MergeRegions(data, regions)
+	if err != nil {
+		return hashBytes(data)
+	}
+	return hashBytes(blanked)
+}
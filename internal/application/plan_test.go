@@ -0,0 +1,223 @@
+package application
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"ggami-go/internal/domain"
+	"ggami-go/internal/generator"
+)
+
+// mergeTarget is a target generated once, then edited by its user
+type mergeTarget struct {
+	previous  map[string]string // generated content recorded in the manifest
+	current   map[string]string // content on disk now
+	generated map[string]string // content of the new run
+}
+
+// region renders a file holding a protected region with the given body
+func region(version, body string) string {
+	return "package x\n\n// ggami:begin custom\n" + body + "// ggami:end\n\n// " + version + "\n"
+}
+
+var editedTarget = mergeTarget{
+	previous: map[string]string{
+		"same.go":              "v1",
+		"untouched.go":         "v1",
+		"edited.go":            "v1",
+		"conflict.go":          "v1",
+		"stale.go":             "v1",
+		"stale_edited.go":      "v1",
+		"region.go":            region("v1", ""),
+		"stale_region.go":      region("v1", ""),
+		"templates/index.html": "v1",
+	},
+	current: map[string]string{
+		"same.go":              "v2",
+		"untouched.go":         "v1",
+		"edited.go":            "user",
+		"conflict.go":          "user",
+		"stale.go":             "v1",
+		"stale_edited.go":      "user",
+		"user.go":              "user",
+		"region.go":            region("v1", "user()\n"),
+		"stale_region.go":      region("v1", "user()\n"),
+		"templates/index.html": "v1",
+	},
+	generated: map[string]string{
+		"same.go":      "v2",
+		"untouched.go": "v2",
+		"edited.go":    "v1",
+		"conflict.go":  "v2",
+		"new.go":       "v2",
+		// edited only inside its region, which PreserveRegionsStep copied in
+		"region.go":            region("v2", "user()\n"),
+		"templates/index.html": "v2",
+		domain.ManifestPath:    "v2",
+	},
+}
+
+// setup writes the target and its manifest to a temporary directory and the
+// new run's output to a MemFS
+func (m mergeTarget) setup(t *testing.T) (out *generator.MemFS, root, finalDir string) {
+	t.Helper()
+	finalDir = t.TempDir()
+	manifest := domain.GenerationManifest{Version: 1, Language: "go", Files: map[string]string{}}
+	for path, content := range m.previous {
+		manifest.Files[path] = generatedHash([]byte(content))
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{domain.ManifestPath: string(data)}
+	for path, content := range m.current {
+		files[path] = content
+	}
+	for path, content := range files {
+		full := filepath.Join(finalDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out, root = generator.NewMemFS(), "gen"
+	for path, content := range m.generated {
+		full := filepath.Join(root, filepath.FromSlash(path))
+		if err := out.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := out.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return out, root, finalDir
+}
+
+func TestBuildPlan(t *testing.T) {
+	tests := []struct {
+		merge   bool
+		actions map[string]domain.FileAction
+		deletes []string
+	}{
+		{
+			merge: false,
+			actions: map[string]domain.FileAction{
+				"same.go":              domain.FileUnchanged,
+				"untouched.go":         domain.FileOverwrite,
+				"edited.go":            domain.FileOverwrite,
+				"conflict.go":          domain.FileOverwrite,
+				"new.go":               domain.FileCreate,
+				"region.go":            domain.FileOverwrite,
+				"templates/index.html": domain.FileOverwrite,
+				domain.ManifestPath:    domain.FileOverwrite,
+			},
+			// the whole target is replaced
+			deletes: []string{"stale.go", "stale_edited.go", "stale_region.go", "user.go"},
+		},
+		{
+			merge: true,
+			actions: map[string]domain.FileAction{
+				"same.go":              domain.FileUnchanged,
+				"untouched.go":         domain.FileOverwrite,
+				"edited.go":            domain.FileKeep,
+				"conflict.go":          domain.FileSidecar,
+				"new.go":               domain.FileCreate,
+				"region.go":            domain.FileOverwrite,
+				"templates/index.html": domain.FileOverwrite,
+				// not listed in itself, but always rewritten
+				domain.ManifestPath: domain.FileOverwrite,
+			},
+			// user files and edited stale files stay, regions included
+			deletes: []string{"stale.go"},
+		},
+	}
+	for _, tt := range tests {
+		out, root, finalDir := editedTarget.setup(t)
+		plan, err := buildPlan(out, root, finalDir, tt.merge)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !plan.TargetExists || plan.Merge != tt.merge {
+			t.Errorf("merge %v: plan %+v", tt.merge, plan)
+		}
+		got := map[string]domain.FileAction{}
+		for _, f := range plan.Files {
+			got[f.Path] = f.Action
+		}
+		for path, want := range tt.actions {
+			if got[path] != want {
+				t.Errorf("merge %v: %s is %q, want %q", tt.merge, path, got[path], want)
+			}
+		}
+		if len(got) != len(tt.actions) {
+			t.Errorf("merge %v: planned %v, want %v", tt.merge, got, tt.actions)
+		}
+		var deletes []string
+		for _, f := range plan.Deletes {
+			deletes = append(deletes, f.Path)
+		}
+		if !slices.Equal(deletes, tt.deletes) {
+			t.Errorf("merge %v: deletes %q, want %q", tt.merge, deletes, tt.deletes)
+		}
+	}
+}
+
+func TestBuildPlanFirstRun(t *testing.T) {
+	out, root, _ := editedTarget.setup(t)
+	plan, err := buildPlan(out, root, filepath.Join(t.TempDir(), "missing"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.TargetExists || len(plan.Deletes) != 0 {
+		t.Errorf("plan %+v, want a new target", plan)
+	}
+	for _, f := range plan.Files {
+		if f.Action != domain.FileCreate {
+			t.Errorf("%s is %q, want create", f.Path, f.Action)
+		}
+	}
+}
+
+func TestApplyMergePlan(t *testing.T) {
+	out, root, finalDir := editedTarget.setup(t)
+	plan, err := buildPlan(out, root, finalDir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := applyMergePlan(plan, out, root, finalDir); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string]string{
+		"same.go":                            "v2",
+		"untouched.go":                       "v2",
+		"edited.go":                          "user",
+		"conflict.go":                        "user",
+		"conflict.go" + domain.SidecarSuffix: "v2",
+		"new.go":                             "v2",
+		"stale_edited.go":                    "user",
+		"region.go":                          region("v2", "user()\n"),
+		"stale_region.go":                    region("v1", "user()\n"),
+		"region.go" + domain.SidecarSuffix:   "",
+		"user.go":                            "user",
+		"templates/index.html":               "v2",
+		"stale.go":                           "", // removed
+		"edited.go" + domain.SidecarSuffix:   "", // kept files get no sidecar
+		"templates/index.html" + domain.SidecarSuffix: "",
+	} {
+		data, err := os.ReadFile(filepath.Join(finalDir, filepath.FromSlash(path)))
+		switch {
+		case want == "" && !os.IsNotExist(err):
+			t.Errorf("%s exists: %q", path, data)
+		case want != "" && string(data) != want:
+			t.Errorf("%s = %q, %v; want %q", path, data, err, want)
+		}
+	}
+}
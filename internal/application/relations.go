@@ -0,0 +1,185 @@
+package application
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"ggami-go/internal/domain"
+)
+
+var (
+	exportedIdentRe = regexp.MustCompile(`^[A-Z][A-Za-z0-9_]*$`)
+	sqlIdentRe      = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// validateRelations checks model associations: targets must exist, names
+// must not clash with fields, and required belongsTo keys must not form a
+// cycle. Required keys are NOT NULL and handlers reject a zero key, so such
+// a cycle, a self-reference included, could never be inserted; a cycle with
+// an optional key is fine, as that key can be set after the insert.
+func validateRelations(models []domain.ModelDef) error {
+	byName := make(map[string]domain.ModelDef)
+	for _, m := range models {
+		byName[m.Name] = m
+	}
+
+	// belongsTo edges by key, including the implicit ones created by hasMany;
+	// a key is optional when any relation using it says so
+	type edge struct {
+		child, parent string
+		optional      bool
+	}
+	var keys []string
+	edges := make(map[string]*edge)
+	addEdge := func(child, fk, parent string, optional bool) {
+		key := child + "." + fk
+		if e, ok := edges[key]; ok {
+			e.optional = e.optional || optional
+			return
+		}
+		keys = append(keys, key)
+		edges[key] = &edge{child, parent, optional}
+	}
+
+	for _, m := range models {
+		names := make(map[string]bool)
+		for _, f := range m.Fields {
+			names[f.Name] = true
+		}
+
+		for _, rel := range m.Relations {
+			if rel.Name == "" {
+				return fmt.Errorf("relation name cannot be empty in model %q", m.Name)
+			}
+			if !exportedIdentRe.MatchString(rel.Name) {
+				return fmt.Errorf("relation name %q in model %q must be an exported Go identifier (PascalCase)", rel.Name, m.Name)
+			}
+			if names[rel.Name] {
+				return fmt.Errorf("relation %q in model %q clashes with a field or relation of the same name", rel.Name, m.Name)
+			}
+			names[rel.Name] = true
+
+			target, ok := byName[rel.Model]
+			if !ok {
+				return fmt.Errorf("relation %q in model %q refers to unknown model %q", rel.Name, m.Name, rel.Model)
+			}
+			if !hasIDField(target) {
+				return fmt.Errorf("relation %q in model %q: model %q needs an ID field", rel.Name, m.Name, target.Name)
+			}
+			if rel.DisplayField != "" && !hasField(target, rel.DisplayField) {
+				return fmt.Errorf("relation %q in model %q: display field %q does not exist in model %q",
+					rel.Name, m.Name, rel.DisplayField, target.Name)
+			}
+
+			switch rel.Type {
+			case domain.RelationBelongsTo:
+				fk := rel.ResolvedForeignKey(m.Name)
+				if err := checkForeignKey(m, fk); err != nil {
+					return fmt.Errorf("relation %q in model %q: %w", rel.Name, m.Name, err)
+				}
+				addEdge(m.Name, fk, target.Name, rel.Optional)
+			case domain.RelationHasMany:
+				fk := rel.ResolvedForeignKey(m.Name)
+				if err := checkForeignKey(target, fk); err != nil {
+					return fmt.Errorf("relation %q in model %q: %w", rel.Name, m.Name, err)
+				}
+				addEdge(target.Name, fk, m.Name, rel.Optional)
+			case domain.RelationMany2Many:
+				if rel.JoinTable != "" && !sqlIdentRe.MatchString(rel.JoinTable) {
+					return fmt.Errorf("relation %q in model %q: invalid join table name %q", rel.Name, m.Name, rel.JoinTable)
+				}
+			default:
+				return fmt.Errorf("relation %q in model %q has unsupported type %q (use belongsTo, hasMany or many2many)",
+					rel.Name, m.Name, rel.Type)
+			}
+		}
+	}
+
+	parents := make(map[string][]string)
+	for _, key := range keys {
+		if e := edges[key]; !e.optional {
+			parents[e.child] = append(parents[e.child], e.parent)
+		}
+	}
+	if cycle := findCycle(models, parents); cycle != nil {
+		return fmt.Errorf("belongsTo cycle detected: %s (a required foreign key chain cannot loop back; make one of its relations optional)",
+			strings.Join(cycle, " → "))
+	}
+	return nil
+}
+
+// checkForeignKey verifies that a declared foreign key field is an integer.
+// Missing keys are fine: the generator adds them.
+func checkForeignKey(m domain.ModelDef, fk string) error {
+	for _, f := range m.Fields {
+		if f.Name == fk {
+			if f.Type != "uint" && f.Type != "int" {
+				return fmt.Errorf("foreign key %s.%s must be uint or int, not %q", m.Name, fk, f.Type)
+			}
+			return nil
+		}
+	}
+	for _, rel := range m.Relations {
+		if rel.Name == fk {
+			return fmt.Errorf("foreign key %s.%s clashes with a relation name", m.Name, fk)
+		}
+	}
+	return nil
+}
+
+func hasIDField(m domain.ModelDef) bool {
+	return hasField(m, "ID")
+}
+
+func hasField(m domain.ModelDef, name string) bool {
+	for _, f := range m.Fields {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// findCycle returns the first cycle in the child → parent graph, or nil
+func findCycle(models []domain.ModelDef, parents map[string][]string) []string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int)
+	var stack []string
+
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		state[name] = visiting
+		stack = append(stack, name)
+		for _, p := range parents[name] {
+			switch state[p] {
+			case visiting:
+				for i, n := range stack {
+					if n == p {
+						return append(append([]string{}, stack[i:]...), p)
+					}
+				}
+			case unvisited:
+				if cycle := visit(p); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = done
+		return nil
+	}
+
+	for _, m := range models {
+		if state[m.Name] == unvisited {
+			if cycle := visit(m.Name); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
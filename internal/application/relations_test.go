@@ -0,0 +1,56 @@
+package application
+
+import (
+	"strings"
+	"testing"
+
+	"ggami-go/internal/domain"
+)
+
+func TestValidateRelationsCycles(t *testing.T) {
+	id := domain.FieldDef{Name: "ID", Type: "uint", GormTags: []string{"primaryKey"}}
+	model := func(name string, rels ...domain.RelationDef) domain.ModelDef {
+		return domain.ModelDef{Name: name, Fields: []domain.FieldDef{id}, Relations: rels}
+	}
+	belongsTo := func(name, target string, optional bool) domain.RelationDef {
+		return domain.RelationDef{Name: name, Type: domain.RelationBelongsTo, Model: target, Optional: optional}
+	}
+	tests := []struct {
+		name   string
+		models []domain.ModelDef
+		cycle  string // "" when valid
+	}{
+		{"required self-reference", []domain.ModelDef{
+			model("Category", belongsTo("Parent", "Category", false)),
+		}, "Category → Category"},
+		{"optional self-reference", []domain.ModelDef{
+			model("Category", belongsTo("Parent", "Category", true)),
+		}, ""},
+		{"required cycle", []domain.ModelDef{
+			model("Team", belongsTo("Captain", "Player", false)),
+			model("Player", belongsTo("Team", "Team", false)),
+		}, "Team → Player → Team"},
+		{"cycle through an optional key", []domain.ModelDef{
+			model("Team", belongsTo("Captain", "Player", true)),
+			model("Player", belongsTo("Team", "Team", false)),
+		}, ""},
+		{"required hasMany closing a cycle", []domain.ModelDef{
+			model("Team", belongsTo("Captain", "Player", false),
+				domain.RelationDef{Name: "Players", Type: domain.RelationHasMany, Model: "Player"}),
+			model("Player"),
+		}, "Team → Player → Team"},
+		{"hasMany sharing an optional key", []domain.ModelDef{
+			model("Category", belongsTo("Parent", "Category", true),
+				domain.RelationDef{Name: "Children", Type: domain.RelationHasMany, Model: "Category", ForeignKey: "ParentID"}),
+		}, ""},
+	}
+	for _, tt := range tests {
+		err := validateRelations(tt.models)
+		switch {
+		case tt.cycle == "" && err != nil:
+			t.Errorf("%s: %v", tt.name, err)
+		case tt.cycle != "" && (err == nil || !strings.Contains(err.Error(), tt.cycle)):
+			t.Errorf("%s: error %v, want a cycle %s", tt.name, err, tt.cycle)
+		}
+	}
+}
@@ -1,10 +1,14 @@
 package application
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/mail"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 
 	"ggami-go/internal/domain"
@@ -29,6 +33,12 @@ var goReservedWords = map[string]bool{
 	"continue": true, "for": true, "import": true, "return": true, "var": true,
 }
 
+// model names taken by types every generated project declares
+var generatedTypeNames = map[string]string{
+	"Auth": "handlers.AuthHandler",
+	"Base": "handlers.BaseHandler",
+}
+
 func (s *ValidateConfigStep) Execute(ctx *domain.PipelineContext) error {
 	c := ctx.Config
 
@@ -47,6 +57,7 @@ func (s *ValidateConfigStep) Execute(ctx *domain.PipelineContext) error {
 
 	if c.GormMode && len(c.Models) > 0 {
 		modelNames := make(map[string]bool)
+		auditing := false
 		for _, m := range c.Models {
 			if m.Name == "" {
 				return fmt.Errorf("model name cannot be empty")
@@ -55,6 +66,9 @@ func (s *ValidateConfigStep) Execute(ctx *domain.PipelineContext) error {
 			if goReservedWords[lower] {
 				return fmt.Errorf("model name %q is a Go reserved word", m.Name)
 			}
+			if clash, ok := generatedTypeNames[m.Name]; ok {
+				return fmt.Errorf("model name %q clashes with the generated %s; rename the model", m.Name, clash)
+			}
 			if modelNames[lower] {
 				return fmt.Errorf("duplicate model name %q", m.Name)
 			}
@@ -68,11 +82,47 @@ func (s *ValidateConfigStep) Execute(ctx *domain.PipelineContext) error {
 					return fmt.Errorf("field name cannot be empty in model %q", m.Name)
 				}
 				if !isValidFieldType(f.Type) {
-					return fmt.Errorf("invalid field type %q for field %q in model %q", f.Type, f.Name, m.Name)
+					return fmt.Errorf("invalid field type %q for field %q in model %q (supported: %s)",
+						f.Type, f.Name, m.Name, strings.Join(generator.FieldTypeNames(), ", "))
+				}
+				if err := validateEnumValues(f); err != nil {
+					return fmt.Errorf("field %q in model %q: %w", f.Name, m.Name, err)
+				}
+				if err := validateFieldRules(f); err != nil {
+					return fmt.Errorf("field %q in model %q: %w", f.Name, m.Name, err)
+				}
+			}
+			if err := validateModelOptions(m); err != nil {
+				return fmt.Errorf("model %q: %w", m.Name, err)
+			}
+			if err := validateScopeFields(m, c.RBAC != nil && c.RBAC.Enabled); err != nil {
+				return fmt.Errorf("model %q: %w", m.Name, err)
+			}
+			if m.Audit {
+				auditing = true
+			}
+		}
+		if auditing && modelNames["auditlog"] {
+			return fmt.Errorf("model name \"AuditLog\" clashes with the generated audit log model; rename the model")
+		}
+		if c.RBAC != nil && c.RBAC.Enabled && modelNames["passwordreset"] {
+			return fmt.Errorf("model name \"PasswordReset\" clashes with the generated password reset model; rename the model")
+		}
+		if c.RBAC != nil && c.RBAC.Enabled && modelNames["session"] {
+			return fmt.Errorf("model name \"Session\" clashes with the generated session model; rename the model")
+		}
+		if c.RBAC != nil && c.RBAC.Enabled && c.RBAC.TwoFactor {
+			for _, name := range []string{"RecoveryCode", "TwoFactorRole"} {
+				if modelNames[strings.ToLower(name)] {
+					return fmt.Errorf("model name %q clashes with the generated two-factor authentication model; rename the model", name)
 				}
 			}
 		}
 
+		if err := validateRelations(c.Models); err != nil {
+			return err
+		}
+
 		if c.DBType != "" && c.DBType != domain.DBTypeMSSQL && c.DBType != domain.DBTypePostgres &&
 			c.DBType != domain.DBTypeMySQL && c.DBType != domain.DBTypeSQLite {
 			return fmt.Errorf("unsupported database type %q", c.DBType)
@@ -80,20 +130,262 @@ func (s *ValidateConfigStep) Execute(ctx *domain.PipelineContext) error {
 	}
 
 	if c.RBAC != nil && c.RBAC.Enabled {
-		if len(c.RBAC.Roles) == 0 {
-			return fmt.Errorf("RBAC enabled but no roles defined")
+		if err := validateRBAC(c.RBAC, c.Models); err != nil {
+			return err
+		}
+	}
+
+	if err := validateStorage(c.Storage); err != nil {
+		return fmt.Errorf("storage: %w", err)
+	}
+
+	if err := validateMail(c.Mail); err != nil {
+		return fmt.Errorf("mail: %w", err)
+	}
+
+	return nil
+}
+
+// validateMail checks the password reset mailer settings
+func validateMail(m *domain.MailConfig) error {
+	if m == nil {
+		return nil
+	}
+	switch m.Driver {
+	case "", "console", "file":
+	case "smtp":
+		if m.SMTP == nil || m.SMTP.Host == "" {
+			return fmt.Errorf("the smtp driver needs smtp.host")
 		}
+	default:
+		return fmt.Errorf("unsupported driver %q (use \"console\", \"smtp\" or \"file\")", m.Driver)
 	}
+	if m.SMTP != nil && (m.SMTP.Port < 0 || m.SMTP.Port > 65535) {
+		return fmt.Errorf("smtp port %d is out of range", m.SMTP.Port)
+	}
+	if m.From != "" {
+		if _, err := mail.ParseAddress(m.From); err != nil {
+			return fmt.Errorf("from %q is not a mail address", m.From)
+		}
+	}
+	if m.BaseURL != "" {
+		if u, err := url.Parse(m.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("baseURL %q is not an absolute http(s) URL", m.BaseURL)
+		}
+	}
+	return nil
+}
 
+// validateStorage checks the upload store settings
+func validateStorage(s *domain.StorageConfig) error {
+	if s == nil {
+		return nil
+	}
+	switch s.Driver {
+	case "", "local":
+	case "s3":
+		if s.S3 == nil || s.S3.Endpoint == "" || s.S3.Bucket == "" {
+			return fmt.Errorf("the s3 driver needs s3.endpoint and s3.bucket")
+		}
+		if n := len(s.S3.Bucket); n < 3 || n > 63 {
+			return fmt.Errorf("s3 bucket names have 3 to 63 characters, not %d", n)
+		}
+	default:
+		return fmt.Errorf("unsupported driver %q (use \"local\" or \"s3\")", s.Driver)
+	}
+	if s.MaxSizeMB < 0 {
+		return fmt.Errorf("maxSizeMB cannot be negative")
+	}
+	for _, t := range s.AllowedTypes {
+		if typ, sub, ok := strings.Cut(t, "/"); !ok || typ == "" || sub == "" || strings.ContainsAny(t, " ;,") {
+			return fmt.Errorf("allowed type %q is not a MIME type such as image/png or image/*", t)
+		}
+	}
+	return nil
+}
+
+// validateRBAC checks that roles are named and unique and that modelPerms
+// name existing models and roles, each once
+func validateRBAC(rbac *domain.RBACConfig, models []domain.ModelDef) error {
+	if len(rbac.Roles) == 0 {
+		return fmt.Errorf("RBAC enabled but no roles defined")
+	}
+	roles := make(map[string]bool)
+	for _, role := range rbac.Roles {
+		if strings.TrimSpace(role) == "" {
+			return fmt.Errorf("role names cannot be empty")
+		}
+		if roles[role] {
+			return fmt.Errorf("duplicate role %q", role)
+		}
+		roles[role] = true
+	}
+	for _, role := range rbac.AdminRoles {
+		if !roles[role] {
+			return fmt.Errorf("adminRoles: unknown role %q (roles: %s)", role, strings.Join(rbac.Roles, ", "))
+		}
+	}
+	if rbac.DefaultRole != "" && !roles[rbac.DefaultRole] {
+		return fmt.Errorf("defaultRole: unknown role %q (roles: %s)", rbac.DefaultRole, strings.Join(rbac.Roles, ", "))
+	}
+	if rbac.TwoFactor && len(rbac.AdminRoles) == 0 {
+		return fmt.Errorf("twoFactor needs adminRoles: admins pick the roles that must use two-factor authentication on the users page")
+	}
+	if rl := rbac.RateLimit; rl != nil {
+		for group, limit := range map[string]int{"auth": rl.Auth, "dashboard": rl.Dashboard, "models": rl.Models} {
+			if limit < -1 {
+				return fmt.Errorf("rateLimit.%s: %d requests per minute; use -1 for no limit", group, limit)
+			}
+		}
+	}
+
+	modelNames := make(map[string]bool)
+	for _, m := range models {
+		modelNames[m.Name] = true
+	}
+	seen := make(map[string]bool)
+	for _, mp := range rbac.ModelPerms {
+		if !modelNames[mp.ModelName] {
+			return fmt.Errorf("modelPerms: unknown model %q", mp.ModelName)
+		}
+		if seen[mp.ModelName] {
+			return fmt.Errorf("modelPerms: duplicate entry for model %q", mp.ModelName)
+		}
+		seen[mp.ModelName] = true
+
+		granted := make(map[string]bool)
+		for _, p := range mp.Permissions {
+			if !roles[p.Role] {
+				return fmt.Errorf("modelPerms of %q: unknown role %q (roles: %s)", mp.ModelName, p.Role, strings.Join(rbac.Roles, ", "))
+			}
+			if granted[p.Role] {
+				return fmt.Errorf("modelPerms of %q: duplicate permissions for role %q", mp.ModelName, p.Role)
+			}
+			granted[p.Role] = true
+		}
+	}
 	return nil
 }
 
 func isValidFieldType(t string) bool {
-	switch t {
-	case "string", "int", "uint", "float64", "bool", "time.Time":
-		return true
+	_, ok := generator.LookupFieldType(t)
+	return ok
+}
+
+// validateModelOptions rejects fields that clash with the ones the timestamps
+// and softDelete options add
+func validateModelOptions(m domain.ModelDef) error {
+	added := map[string]string{}
+	if m.Timestamps {
+		added["CreatedAt"], added["UpdatedAt"] = "timestamps", "timestamps"
+	}
+	if m.SoftDelete {
+		added["DeletedAt"] = "softDelete"
+	}
+	for _, f := range m.Fields {
+		if option, ok := added[f.Name]; ok {
+			return fmt.Errorf("field %q is added by the %s option; remove the field", f.Name, option)
+		}
+	}
+	return nil
+}
+
+// validateScopeFields checks the ownerField and tenantField options: they
+// need RBAC for the signed-in user, and a declared field of that name must be
+// a uint other than the primary key
+func validateScopeFields(m domain.ModelDef, rbac bool) error {
+	if m.OwnerField == "" && m.TenantField == "" {
+		return nil
+	}
+	if !rbac {
+		return fmt.Errorf("ownerField and tenantField need RBAC enabled")
+	}
+	if m.OwnerField == m.TenantField {
+		return fmt.Errorf("ownerField and tenantField must be different fields")
+	}
+	for _, opt := range []struct{ option, name string }{{"ownerField", m.OwnerField}, {"tenantField", m.TenantField}} {
+		option, name := opt.option, opt.name
+		if name == "" {
+			continue
+		}
+		if !exportedIdentRe.MatchString(name) {
+			return fmt.Errorf("%s %q must be an exported Go identifier (PascalCase)", option, name)
+		}
+		for _, f := range m.Fields {
+			if f.Name != name {
+				continue
+			}
+			if f.Type != "uint" {
+				return fmt.Errorf("%s %q must be a uint field, not %s", option, name, f.Type)
+			}
+			if slices.Contains(f.GormTags, "primaryKey") {
+				return fmt.Errorf("%s %q cannot be the primary key", option, name)
+			}
+		}
+	}
+	return nil
+}
+
+// validateEnumValues requires a non-empty, duplicate-free value list for
+// enum fields and rejects values on other types
+func validateEnumValues(f domain.FieldDef) error {
+	if f.Type != "enum" {
+		if len(f.EnumValues) > 0 {
+			return fmt.Errorf("enumValues is only allowed on enum fields")
+		}
+		return nil
+	}
+	if len(f.EnumValues) == 0 {
+		return fmt.Errorf("enum field needs at least one value in enumValues")
+	}
+	seen := make(map[string]bool)
+	for _, v := range f.EnumValues {
+		if strings.TrimSpace(v) == "" {
+			return fmt.Errorf("enum values cannot be empty")
+		}
+		if len(v) > 64 {
+			return fmt.Errorf("enum value %q is longer than 64 characters", v)
+		}
+		if seen[v] {
+			return fmt.Errorf("duplicate enum value %q", v)
+		}
+		seen[v] = true
+	}
+	return nil
+}
+
+// validateFieldRules checks that validation rules fit the field type
+func validateFieldRules(f domain.FieldDef) error {
+	v := f.Validation
+	if v == nil {
+		return nil
+	}
+	ft, _ := generator.LookupFieldType(f.Type)
+
+	if (v.Min != nil || v.Max != nil) && !ft.Numeric {
+		return fmt.Errorf("min/max only apply to numeric types, not %q", f.Type)
+	}
+	if v.Min != nil && v.Max != nil && *v.Min > *v.Max {
+		return fmt.Errorf("min %v is greater than max %v", *v.Min, *v.Max)
+	}
+	if (v.MinLength != 0 || v.MaxLength != 0 || v.Pattern != "" || v.Email) && !ft.Textual {
+		return fmt.Errorf("minLength/maxLength/pattern/email only apply to string and text fields, not %q", f.Type)
 	}
-	return false
+	if v.MinLength < 0 || v.MaxLength < 0 {
+		return fmt.Errorf("minLength and maxLength cannot be negative")
+	}
+	if v.MaxLength > 0 && v.MinLength > v.MaxLength {
+		return fmt.Errorf("minLength %d is greater than maxLength %d", v.MinLength, v.MaxLength)
+	}
+	if v.Pattern != "" {
+		if _, err := regexp.Compile(v.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+	}
+	if v.Unique && (f.Type == "json" || f.Type == "file" || f.Type == "image" || f.Type == "bool") {
+		return fmt.Errorf("unique is not supported for %q fields", f.Type)
+	}
+	return nil
 }
 
 func (s *ValidateConfigStep) Rollback(ctx *domain.PipelineContext) error { return nil }
@@ -126,16 +418,31 @@ type CreateTempDirStep struct{}
 func (s *CreateTempDirStep) Name() string { return "CreateTempDir" }
 
 func (s *CreateTempDirStep) Execute(ctx *domain.PipelineContext) error {
+	if ctx.DryRun {
+		// Dry run: render into memory under a virtual temp dir
+		mem := generator.NewMemFS()
+		dir := filepath.Join(os.TempDir(), "ggami-plan")
+		if err := mem.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("create in-memory temp dir: %w", err)
+		}
+		ctx.TempDir = dir
+		ctx.Output = mem
+		return nil
+	}
+
 	dir, err := os.MkdirTemp("", "ggami-gen-*")
 	if err != nil {
 		return fmt.Errorf("create temp dir: %w", err)
 	}
 	ctx.TempDir = dir
+	if ctx.Output == nil {
+		ctx.Output = generator.DiskFS{}
+	}
 	return nil
 }
 
 func (s *CreateTempDirStep) Rollback(ctx *domain.PipelineContext) error {
-	if ctx.TempDir != "" {
+	if ctx.TempDir != "" && !ctx.DryRun {
 		return os.RemoveAll(ctx.TempDir)
 	}
 	return nil
@@ -148,7 +455,7 @@ type ScaffoldStep struct{}
 func (s *ScaffoldStep) Name() string { return "Scaffold" }
 
 func (s *ScaffoldStep) Execute(ctx *domain.PipelineContext) error {
-	gen, err := generator.NewGenerator(ctx.Language)
+	gen, err := generator.NewGeneratorWithFS(ctx.Language, output(ctx))
 	if err != nil {
 		return err
 	}
@@ -164,7 +471,7 @@ type ScaffoldGormStep struct{}
 func (s *ScaffoldGormStep) Name() string { return "ScaffoldGorm" }
 
 func (s *ScaffoldGormStep) Execute(ctx *domain.PipelineContext) error {
-	return generator.ScaffoldGorm(ctx.TempDir)
+	return generator.ScaffoldGorm(output(ctx), ctx.TempDir)
 }
 
 func (s *ScaffoldGormStep) Rollback(ctx *domain.PipelineContext) error { return nil }
@@ -176,7 +483,7 @@ type GenerateCoreStep struct{}
 func (s *GenerateCoreStep) Name() string { return "GenerateCore" }
 
 func (s *GenerateCoreStep) Execute(ctx *domain.PipelineContext) error {
-	gen, err := generator.NewGenerator(ctx.Language)
+	gen, err := generator.NewGeneratorWithFS(ctx.Language, output(ctx))
 	if err != nil {
 		return err
 	}
@@ -197,7 +504,7 @@ func (s *GenerateModelsStep) Name() string { return "GenerateModels" }
 func (s *GenerateModelsStep) Execute(ctx *domain.PipelineContext) error {
 	cfg := ctx.Config
 	cfg.TargetPath = ctx.TempDir
-	return generator.RenderModels(cfg)
+	return generator.RenderModels(output(ctx), cfg)
 }
 
 func (s *GenerateModelsStep) Rollback(ctx *domain.PipelineContext) error { return nil }
@@ -211,7 +518,7 @@ func (s *GenerateHandlersStep) Name() string { return "GenerateHandlers" }
 func (s *GenerateHandlersStep) Execute(ctx *domain.PipelineContext) error {
 	cfg := ctx.Config
 	cfg.TargetPath = ctx.TempDir
-	return generator.RenderHandlers(cfg)
+	return generator.RenderHandlers(output(ctx), cfg)
 }
 
 func (s *GenerateHandlersStep) Rollback(ctx *domain.PipelineContext) error { return nil }
@@ -225,11 +532,57 @@ func (s *GenerateTemplatesStep) Name() string { return "GenerateTemplates" }
 func (s *GenerateTemplatesStep) Execute(ctx *domain.PipelineContext) error {
 	cfg := ctx.Config
 	cfg.TargetPath = ctx.TempDir
-	return generator.RenderHTMLTemplates(cfg)
+	return generator.RenderHTMLTemplates(output(ctx), cfg)
 }
 
 func (s *GenerateTemplatesStep) Rollback(ctx *domain.PipelineContext) error { return nil }
 
+// --- Step 8b: GenerateMigrationsStep (GORM mode) ---
+
+type GenerateMigrationsStep struct{}
+
+func (s *GenerateMigrationsStep) Name() string { return "GenerateMigrations" }
+
+// Execute keeps the migrations already in FinalDir and adds one for the
+// schema changes since the config recorded in its manifest. Changes that
+// can lose data fail the run unless AllowDestructive is set.
+func (s *GenerateMigrationsStep) Execute(ctx *domain.PipelineContext) error {
+	cfg := ctx.Config
+	cfg.TargetPath = ctx.TempDir
+	out := output(ctx)
+
+	previous, err := previousConfig(ctx.FinalDir)
+	if err != nil {
+		return err
+	}
+	latest, err := copyMigrationHistory(out, ctx.FinalDir, ctx.TempDir)
+	if err != nil {
+		return fmt.Errorf("copy migrations: %w", err)
+	}
+	if latest == 0 {
+		return generator.RenderMigrations(out, cfg)
+	}
+	if previous == nil {
+		// Generated before configs were recorded: keep the history as is
+		ctx.Warnings = append(ctx.Warnings, "the manifest records no previous config; schema changes since the last generation were not migrated")
+		previous = &ctx.Config
+	}
+
+	diff := generator.DiffConfigs(*previous, ctx.Config)
+	if len(diff.Destructive) > 0 && !ctx.AllowDestructive {
+		if !ctx.DryRun {
+			return fmt.Errorf("schema changes can lose data; confirm them to continue:\n  %s",
+				strings.Join(diff.Destructive, "\n  "))
+		}
+		for _, change := range diff.Destructive {
+			ctx.Warnings = append(ctx.Warnings, "destructive schema change needs confirmation: "+change)
+		}
+	}
+	return generator.RenderSchemaChange(out, *previous, cfg, latest+1)
+}
+
+func (s *GenerateMigrationsStep) Rollback(ctx *domain.PipelineContext) error { return nil }
+
 // --- Step 9: GenerateMiddlewareStep (GORM + RBAC) ---
 
 type GenerateMiddlewareStep struct{}
@@ -239,7 +592,7 @@ func (s *GenerateMiddlewareStep) Name() string { return "GenerateMiddleware" }
 func (s *GenerateMiddlewareStep) Execute(ctx *domain.PipelineContext) error {
 	cfg := ctx.Config
 	cfg.TargetPath = ctx.TempDir
-	return generator.RenderMiddleware(cfg)
+	return generator.RenderMiddleware(output(ctx), cfg)
 }
 
 func (s *GenerateMiddlewareStep) Rollback(ctx *domain.PipelineContext) error { return nil }
@@ -254,7 +607,7 @@ func (s *InjectModulesStep) Execute(ctx *domain.PipelineContext) error {
 	cfg := ctx.Config
 	cfg.TargetPath = ctx.TempDir
 	cfg.Modules = moduleIDs(ctx.Modules)
-	return generator.GenerateLegacyCode(cfg)
+	return generator.GenerateLegacyCode(output(ctx), cfg)
 }
 
 func moduleIDs(mods []domain.ModuleDef) []string {
@@ -267,13 +620,117 @@ func moduleIDs(mods []domain.ModuleDef) []string {
 
 func (s *InjectModulesStep) Rollback(ctx *domain.PipelineContext) error { return nil }
 
-// --- Step 11: FinalizeStep ---
+// --- Step 11: PreserveRegionsStep ---
+
+type PreserveRegionsStep struct{}
+
+func (s *PreserveRegionsStep) Name() string { return "PreserveRegions" }
+
+// Execute copies protected region bodies from files already in FinalDir
+// into the freshly rendered files
+func (s *PreserveRegionsStep) Execute(ctx *domain.PipelineContext) error {
+	if _, err := os.Stat(ctx.FinalDir); err != nil {
+		return nil // nothing generated there yet
+	}
+
+	out := output(ctx)
+	return out.Walk(ctx.TempDir, func(path string, data []byte) error {
+		if !generator.HasRegions(data) {
+			return nil
+		}
+		rel, err := filepath.Rel(ctx.TempDir, path)
+		if err != nil {
+			return err
+		}
+		existing, err := os.ReadFile(filepath.Join(ctx.FinalDir, rel))
+		if err != nil || !generator.HasRegions(existing) {
+			return nil
+		}
+
+		regions, err := generator.ExtractRegions(existing)
+		if err != nil {
+			return fmt.Errorf("%s: %w", filepath.ToSlash(rel), err)
+		}
+		merged, orphans, err := generator.MergeRegions(data, regions)
+		if err != nil {
+			return fmt.Errorf("%s: %w", filepath.ToSlash(rel), err)
+		}
+		for _, name := range orphans {
+			ctx.Warnings = append(ctx.Warnings, fmt.Sprintf(
+				"%s: protected region %q no longer exists in the generated file; its content was dropped", filepath.ToSlash(rel), name))
+		}
+		return out.WriteFile(path, merged, 0644)
+	})
+}
+
+func (s *PreserveRegionsStep) Rollback(ctx *domain.PipelineContext) error { return nil }
+
+// --- Step 12: WriteManifestStep ---
+
+type WriteManifestStep struct{}
+
+func (s *WriteManifestStep) Name() string { return "WriteManifest" }
+
+func (s *WriteManifestStep) Execute(ctx *domain.PipelineContext) error {
+	out := output(ctx)
+	manifest := domain.GenerationManifest{
+		Version:  1,
+		Language: ctx.Language,
+		Files:    make(map[string]string),
+		Config:   manifestConfig(ctx.Config),
+	}
+	err := out.Walk(ctx.TempDir, func(path string, data []byte) error {
+		rel, err := filepath.Rel(ctx.TempDir, path)
+		if err != nil {
+			return err
+		}
+		manifest.Files[filepath.ToSlash(rel)] = generatedHash(data)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("hash generated files: %w", err)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(ctx.TempDir, filepath.FromSlash(domain.ManifestPath))
+	if err := out.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return out.WriteFile(path, data, 0644)
+}
+
+func (s *WriteManifestStep) Rollback(ctx *domain.PipelineContext) error { return nil }
+
+// --- Step 13: FinalizeStep ---
 
 type FinalizeStep struct{}
 
 func (s *FinalizeStep) Name() string { return "Finalize" }
 
 func (s *FinalizeStep) Execute(ctx *domain.PipelineContext) error {
+	if ctx.DryRun || ctx.Merge {
+		plan, err := buildPlan(output(ctx), ctx.TempDir, ctx.FinalDir, ctx.Merge)
+		if err != nil {
+			return fmt.Errorf("build plan: %w", err)
+		}
+		ctx.Plan = plan
+		if ctx.DryRun {
+			// Report what would happen to FinalDir instead of touching it
+			return nil
+		}
+
+		// Merge: write only files the user has not modified
+		if err := applyMergePlan(plan, output(ctx), ctx.TempDir, ctx.FinalDir); err != nil {
+			return fmt.Errorf("merge into target: %w", err)
+		}
+		os.RemoveAll(ctx.TempDir)
+		ctx.TempDir = "" // prevent rollback from cleaning up
+		return nil
+	}
+
 	// Remove existing target if present
 	if _, err := os.Stat(ctx.FinalDir); err == nil {
 		if err := os.RemoveAll(ctx.FinalDir); err != nil {
@@ -300,6 +757,14 @@ func (s *FinalizeStep) Execute(ctx *domain.PipelineContext) error {
 
 func (s *FinalizeStep) Rollback(ctx *domain.PipelineContext) error { return nil }
 
+// output returns the filesystem steps render into
+func output(ctx *domain.PipelineContext) domain.OutputFS {
+	if ctx.Output == nil {
+		return generator.DiskFS{}
+	}
+	return ctx.Output
+}
+
 // copyDir recursively copies a directory tree
 func copyDir(src, dst string) error {
 	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
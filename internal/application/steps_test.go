@@ -0,0 +1,89 @@
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"ggami-go/internal/domain"
+	"ggami-go/internal/generator"
+)
+
+// regionsContext renders generated into a MemFS and writes existing to a
+// final directory on disk, both keyed by relative path
+func regionsContext(t *testing.T, generated, existing map[string]string) (*domain.PipelineContext, *generator.MemFS) {
+	t.Helper()
+	ctx := &domain.PipelineContext{TempDir: "tmp", FinalDir: t.TempDir()}
+	out := generator.NewMemFS()
+	ctx.Output = out
+	for rel, content := range generated {
+		path := filepath.Join(ctx.TempDir, filepath.FromSlash(rel))
+		if err := out.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := out.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for rel, content := range existing {
+		path := filepath.Join(ctx.FinalDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return ctx, out
+}
+
+func TestPreserveRegionsStep(t *testing.T) {
+	generated := map[string]string{
+		"main.go": "package main\n\n// ggami:begin custom-routes\n// ggami:end\n\nfunc main() {}\n",
+		"templates/form.html": "<form>\n<!-- ggami:begin extra-fields -->\n<!-- ggami:end -->\n" +
+			"<!-- ggami:begin actions -->\n<button>Save</button>\n<!-- ggami:end -->\n</form>\n",
+		"new.go": "package main\n\n// ggami:begin custom\n// ggami:end\n",
+	}
+	existing := map[string]string{
+		"main.go": "package main\n\n// ggami:begin custom-routes\nroute(\"/health\")\n// ggami:end\n\nfunc main() { changed() }\n",
+		// "extra-fields" was renamed to "fields" by hand; "actions" is missing
+		"templates/form.html": "<form>\n<!-- ggami:begin fields -->\n<input name=\"note\">\n<!-- ggami:end -->\n</form>\n",
+	}
+	ctx, out := regionsContext(t, generated, existing)
+	if err := (&PreserveRegionsStep{}).Execute(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for rel, want := range map[string]string{
+		// only the region body is kept; the rest is the new output
+		"main.go":             "package main\n\n// ggami:begin custom-routes\nroute(\"/health\")\n// ggami:end\n\nfunc main() {}\n",
+		"templates/form.html": generated["templates/form.html"],
+		"new.go":              generated["new.go"],
+	} {
+		got, err := out.ReadFile(filepath.Join(ctx.TempDir, filepath.FromSlash(rel)))
+		if err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v; want %q", rel, got, err, want)
+		}
+	}
+	want := []string{`templates/form.html: protected region "fields" no longer exists in the generated file; its content was dropped`}
+	if !slices.Equal(ctx.Warnings, want) {
+		t.Errorf("warnings %q, want %q", ctx.Warnings, want)
+	}
+}
+
+func TestPreserveRegionsStepUnclosed(t *testing.T) {
+	ctx, out := regionsContext(t,
+		map[string]string{"main.go": "package main\n\n// ggami:begin custom-routes\n// ggami:end\n"},
+		map[string]string{"main.go": "package main\n\n// ggami:begin custom-routes\nroute(\"/health\")\n"},
+	)
+	err := (&PreserveRegionsStep{}).Execute(ctx)
+	if err == nil || !strings.Contains(err.Error(), `main.go: region "custom-routes" is not closed`) {
+		t.Fatalf("error %v, want the unclosed region reported", err)
+	}
+	// the generated file is left as rendered
+	if got, _ := out.ReadFile(filepath.Join(ctx.TempDir, "main.go")); !strings.HasSuffix(string(got), "// ggami:end\n") {
+		t.Errorf("main.go = %q", got)
+	}
+}
@@ -0,0 +1,20 @@
+package domain
+
+// ManifestPath is where the generation manifest lives, relative to the target
+const ManifestPath = ".ggami/manifest.json"
+
+// SidecarSuffix is appended to a user-modified file's path when merge mode
+// writes the freshly generated version next to it
+const SidecarSuffix = ".ggami-new"
+
+// GenerationManifest records the files the generator wrote to a target,
+// so a later merge run can tell generated files from user edits
+type GenerationManifest struct {
+	Version  int               `json:"version"`
+	Language string            `json:"language"`
+	Files    map[string]string `json:"files"` // slash-separated relative path → sha256 of generated content
+
+	// Config is the project config of this generation (without secrets),
+	// diffed against the next one to generate schema migrations
+	Config *ProjectConfig `json:"config,omitempty"`
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "io/fs"
+
+// OutputFS abstracts the filesystem generated files are written to,
+// so the same steps can render to disk or into memory for a dry run
+type OutputFS interface {
+	MkdirAll(path string, perm fs.FileMode) error
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	ReadFile(name string) ([]byte, error)
+	// Walk calls fn for every regular file under root in lexical order
+	Walk(root string, fn func(path string, data []byte) error) error
+}
+
+// FileAction describes what generation would do to a single file
+type FileAction string
+
+const (
+	FileCreate    FileAction = "create"    // file does not exist in the target yet
+	FileOverwrite FileAction = "overwrite" // file exists with different content
+	FileUnchanged FileAction = "unchanged" // file exists with identical content
+	FileDelete    FileAction = "delete"    // file exists in the target but is not generated
+	FileKeep      FileAction = "keep"      // merge: user-modified file is left as is
+	FileSidecar   FileAction = "sidecar"   // merge: user-modified file, new output goes to <path>.ggami-new
+)
+
+// PlannedFile is a single entry of a generation plan
+type PlannedFile struct {
+	Path   string     `json:"path"` // relative to the target, slash-separated
+	Size   int64      `json:"size"`
+	SHA256 string     `json:"sha256"`
+	Action FileAction `json:"action"`
+}
+
+// GenerationPlan is the manifest produced by a dry run
+type GenerationPlan struct {
+	TargetDir    string        `json:"targetDir"`
+	TargetExists bool          `json:"targetExists"`
+	Merge        bool          `json:"merge"`
+	Files        []PlannedFile `json:"files"`   // files the generator would write
+	Deletes      []PlannedFile `json:"deletes"` // existing target files that would be removed
+	Warnings     []string      `json:"warnings"`
+}
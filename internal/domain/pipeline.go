@@ -7,4 +7,13 @@ type PipelineContext struct {
 	TempDir  string      // temporary directory during generation
 	FinalDir string      // final output path
 	Modules  []ModuleDef // dependency-sorted active modules
+
+	DryRun bool            // render into memory and report instead of writing FinalDir
+	Merge  bool            // keep user-modified files in FinalDir instead of replacing it
+	Output OutputFS        // where steps write generated files (disk unless DryRun)
+	Plan   *GenerationPlan // filled by FinalizeStep when DryRun or Merge is set
+
+	AllowDestructive bool // accept schema changes that can lose data (dropped columns, narrowed types)
+
+	Warnings []string // non-fatal problems reported by steps
 }
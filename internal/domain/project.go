@@ -16,21 +16,128 @@ type ProjectConfig struct {
 	Models   []ModelDef `json:"models,omitempty"`
 	DBType   DBType     `json:"dbType,omitempty"`
 	RBAC     *RBACConfig `json:"rbac,omitempty"`
+
+	// AutoMigrate runs gorm AutoMigrate at startup instead of requiring
+	// the generated SQL migrations to be applied with "migrate up"
+	AutoMigrate bool `json:"autoMigrate,omitempty"`
+
+	// Storage configures where file and image fields keep their uploads
+	Storage *StorageConfig `json:"storage,omitempty"`
+
+	// Mail configures how projects with RBAC send password reset links
+	Mail *MailConfig `json:"mail,omitempty"`
+}
+
+// StorageConfig selects the upload store of a generated project. S3
+// credentials are not part of it: the project reads them from the
+// S3_ACCESS_KEY and S3_SECRET_KEY environment variables.
+type StorageConfig struct {
+	Driver       string    `json:"driver,omitempty"`       // "local" (default) or "s3"
+	Root         string    `json:"root,omitempty"`         // local: directory of the files, default "uploads"
+	MaxSizeMB    int       `json:"maxSizeMB,omitempty"`    // per file, default 10
+	AllowedTypes []string  `json:"allowedTypes,omitempty"` // MIME types, "image/*" for a family; default any
+	S3           *S3Config `json:"s3,omitempty"`
+}
+
+// S3Config locates the bucket of an S3-compatible store (AWS S3, MinIO, ...)
+type S3Config struct {
+	Endpoint  string `json:"endpoint"` // host[:port]: "s3.ap-northeast-2.amazonaws.com", "localhost:9000"
+	Region    string `json:"region,omitempty"`
+	Bucket    string `json:"bucket"`
+	UseSSL    bool   `json:"useSSL,omitempty"`
+	PathStyle bool   `json:"pathStyle,omitempty"` // bucket in the URL path, as MinIO expects
+}
+
+// MailConfig selects the mailer of a generated project. SMTP credentials
+// are not part of it: the project reads them from the SMTP_USERNAME and
+// SMTP_PASSWORD environment variables.
+type MailConfig struct {
+	Driver  string      `json:"driver,omitempty"`  // "console" (default), "smtp" or "file"
+	From    string      `json:"from,omitempty"`    // sender address, default "no-reply@localhost"
+	BaseURL string      `json:"baseURL,omitempty"` // app URL that links in mails start with, default "http://localhost:<port>"
+	Dir     string      `json:"dir,omitempty"`     // file: directory of the .eml files, default "mail"
+	SMTP    *SMTPConfig `json:"smtp,omitempty"`
+}
+
+// SMTPConfig locates the SMTP server of the smtp mail driver
+type SMTPConfig struct {
+	Host string `json:"host"`           // "smtp.example.com", "localhost" for a local catcher such as Mailpit
+	Port int    `json:"port,omitempty"` // default 587; Mailpit and MailHog listen on 1025
 }
 
 // FieldDef defines a single field in a GORM model
 type FieldDef struct {
 	Name       string   `json:"name"`       // PascalCase: "Title"
-	Type       string   `json:"type"`       // "string","int","uint","float64","bool","time.Time"
+	Type       string   `json:"type"`       // field type registry name: "string","int","decimal","enum",...
 	GormTags   []string `json:"gormTags"`   // ["primaryKey","unique","not null","index"]
 	DefaultVal string   `json:"defaultVal"` // default value
 	JsonName   string   `json:"jsonName"`   // auto snake_case from frontend
+	EnumValues []string `json:"enumValues,omitempty"` // allowed values for "enum"
+	Validation *FieldValidation `json:"validation,omitempty"`
+}
+
+// FieldValidation holds the rules checked by generated handlers and forms
+type FieldValidation struct {
+	Required  bool     `json:"required,omitempty"`
+	Min       *float64 `json:"min,omitempty"`       // numeric types
+	Max       *float64 `json:"max,omitempty"`       // numeric types
+	MinLength int      `json:"minLength,omitempty"` // string types, in characters
+	MaxLength int      `json:"maxLength,omitempty"` // string types, in characters
+	Pattern   string   `json:"pattern,omitempty"`   // RE2 regex matched against the whole value
+	Email     bool     `json:"email,omitempty"`
+	Unique    bool     `json:"unique,omitempty"` // checked against the database before saving
 }
 
 // ModelDef defines a GORM model with its fields
 type ModelDef struct {
-	Name   string     `json:"name"`   // PascalCase: "Product"
-	Fields []FieldDef `json:"fields"`
+	Name      string        `json:"name"`   // PascalCase: "Product"
+	Fields    []FieldDef    `json:"fields"`
+	Relations []RelationDef `json:"relations,omitempty"`
+	TableName string        `json:"tableName,omitempty"` // overrides GORM's default table name (imported schemas)
+
+	Timestamps bool `json:"timestamps,omitempty"` // CreatedAt and UpdatedAt, set by GORM
+	SoftDelete bool `json:"softDelete,omitempty"` // DeletedAt: Delete moves records to a trash they can be restored from
+	Audit      bool `json:"audit,omitempty"`      // writes to audit_logs who created, changed or deleted a record
+
+	// Row-level scoping (needs RBAC): uint fields set from the signed-in user
+	// on create, added if the model does not declare them
+	OwnerField  string `json:"ownerField,omitempty"`  // creator's user ID: users only see their own records, unless their role is in rbac.adminRoles
+	TenantField string `json:"tenantField,omitempty"` // user's tenant ID: users never see another tenant's records
+}
+
+// RelationType represents a supported association kind
+type RelationType string
+
+const (
+	RelationBelongsTo RelationType = "belongsTo" // Order → Customer (FK on this model)
+	RelationHasMany   RelationType = "hasMany"   // Customer → Orders (FK on the other model)
+	RelationMany2Many RelationType = "many2many" // Product ↔ Tags (join table)
+)
+
+// RelationDef defines an association from one model to another
+type RelationDef struct {
+	Name         string       `json:"name"`                   // association field, PascalCase: "Customer", "Tags"
+	Type         RelationType `json:"type"`                   // "belongsTo","hasMany","many2many"
+	Model        string       `json:"model"`                  // target model name: "Customer"
+	ForeignKey   string       `json:"foreignKey,omitempty"`   // belongsTo: default <Name>ID; hasMany: default <OwnerModel>ID
+	JoinTable    string       `json:"joinTable,omitempty"`    // many2many: default <owner>_<names> in snake_case
+	DisplayField string       `json:"displayField,omitempty"` // target field shown in selects and lists (default: first string field)
+	Optional     bool         `json:"optional,omitempty"`     // belongsTo and hasMany: the key may be empty (NULL)
+}
+
+// ResolvedForeignKey returns the foreign key field name for a relation owned by model owner.
+// belongsTo keys live on the owner, hasMany keys live on the target; many2many has none.
+func (r RelationDef) ResolvedForeignKey(owner string) string {
+	if r.ForeignKey != "" {
+		return r.ForeignKey
+	}
+	switch r.Type {
+	case RelationBelongsTo:
+		return r.Name + "ID"
+	case RelationHasMany:
+		return owner + "ID"
+	}
+	return ""
 }
 
 // DBType represents a supported database type
@@ -60,8 +167,21 @@ type ModelRBAC struct {
 
 // RBACConfig holds all RBAC/JWT configuration
 type RBACConfig struct {
-	Enabled    bool        `json:"enabled"`
-	Roles      []string    `json:"roles"`
-	JWTSecret  string      `json:"jwtSecret"`
-	ModelPerms []ModelRBAC `json:"modelPerms"`
+	Enabled     bool             `json:"enabled"`
+	Roles       []string         `json:"roles"`
+	JWTSecret   string           `json:"jwtSecret"`
+	ModelPerms  []ModelRBAC      `json:"modelPerms"`
+	AdminRoles  []string         `json:"adminRoles,omitempty"`  // roles that see every user's records of models with an ownerField and manage users
+	DefaultRole string           `json:"defaultRole,omitempty"` // role of users who register; default the last of Roles
+	RateLimit   *RateLimitConfig `json:"rateLimit,omitempty"`
+	TwoFactor   bool             `json:"twoFactor,omitempty"` // TOTP two-factor authentication; admins pick the roles that must use it
+}
+
+// RateLimitConfig caps the requests per minute of each client IP to a route
+// group. 0 keeps the default: 30 for the auth routes, no limit for the others;
+// -1 turns a limit off.
+type RateLimitConfig struct {
+	Auth      int `json:"auth,omitempty"`      // login, register, password reset, refresh and logout
+	Dashboard int `json:"dashboard,omitempty"` // /dashboard pages
+	Models    int `json:"models,omitempty"`    // model pages and API
 }
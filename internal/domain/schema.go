@@ -0,0 +1,81 @@
+package domain
+
+// SQLTable describes one table of a generated database schema
+type SQLTable struct {
+	Name        string          `json:"name"`
+	Model       string          `json:"model,omitempty"` // "" for many2many join tables
+	Columns     []SQLColumn     `json:"columns"`
+	PrimaryKey  []string        `json:"primaryKey,omitempty"` // composite key (join tables); single keys are marked on the column
+	Indexes     []SQLIndex      `json:"indexes,omitempty"`
+	ForeignKeys []SQLForeignKey `json:"foreignKeys,omitempty"`
+}
+
+// SQLColumn describes a table column
+type SQLColumn struct {
+	Name          string   `json:"name"`
+	Field         string   `json:"field,omitempty"`   // model field name
+	Kind          string   `json:"kind"`              // field type registry name
+	Size          int      `json:"size,omitempty"`    // substituted for %d in the type's SQLTypes
+	RawType       string   `json:"rawType,omitempty"` // explicit "type:" GORM tag, used as is
+	PrimaryKey    bool     `json:"primaryKey,omitempty"`
+	AutoIncrement bool     `json:"autoIncrement,omitempty"`
+	NotNull       bool     `json:"notNull,omitempty"`
+	Unique        bool     `json:"unique,omitempty"`
+	Default       string   `json:"default,omitempty"`    // unquoted default value
+	EnumValues    []string `json:"enumValues,omitempty"` // imported ENUM('a','b') columns
+}
+
+// SQLIndex describes a secondary index
+type SQLIndex struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique,omitempty"`
+}
+
+// SQLForeignKey describes a foreign key constraint
+type SQLForeignKey struct {
+	Name      string `json:"name"`
+	Column    string `json:"column"`
+	RefTable  string `json:"refTable"`
+	RefColumn string `json:"refColumn"`
+	OnDelete  string `json:"onDelete,omitempty"` // "" or "CASCADE"
+}
+
+// SchemaDiff lists what changed between two schemas, model by model
+type SchemaDiff struct {
+	AddedTables   []SQLTable  `json:"addedTables,omitempty"`
+	RemovedTables []SQLTable  `json:"removedTables,omitempty"`
+	ChangedTables []TableDiff `json:"changedTables,omitempty"`
+	Destructive   []string    `json:"destructive,omitempty"` // changes that can lose data; need confirmation
+}
+
+// TableDiff lists the changes to a table present in both schemas
+type TableDiff struct {
+	Table              string          `json:"table"`
+	Model              string          `json:"model,omitempty"`
+	AddedColumns       []SQLColumn     `json:"addedColumns,omitempty"`
+	RemovedColumns     []SQLColumn     `json:"removedColumns,omitempty"`
+	ChangedColumns     []ColumnChange  `json:"changedColumns,omitempty"`
+	AddedIndexes       []SQLIndex      `json:"addedIndexes,omitempty"`
+	RemovedIndexes     []SQLIndex      `json:"removedIndexes,omitempty"`
+	AddedForeignKeys   []SQLForeignKey `json:"addedForeignKeys,omitempty"`
+	RemovedForeignKeys []SQLForeignKey `json:"removedForeignKeys,omitempty"`
+}
+
+// ColumnChange is a column whose definition changed
+type ColumnChange struct {
+	Old       SQLColumn `json:"old"`
+	New       SQLColumn `json:"new"`
+	Narrowing bool      `json:"narrowing,omitempty"` // existing values may not fit the new type
+}
+
+// Empty reports whether the schemas are identical
+func (d SchemaDiff) Empty() bool {
+	return len(d.AddedTables) == 0 && len(d.RemovedTables) == 0 && len(d.ChangedTables) == 0
+}
+
+// ModelImport is the result of reverse-engineering models from an existing schema
+type ModelImport struct {
+	Models   []ModelDef `json:"models"`
+	Warnings []string   `json:"warnings,omitempty"` // tables, columns or constraints that could not be mapped
+}
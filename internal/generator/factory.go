@@ -2,13 +2,18 @@ package generator
 
 import "fmt"
 
-// NewGenerator creates a generator for the specified language
+// NewGenerator creates a generator for the specified language that writes to disk
 func NewGenerator(lang string) (Generator, error) {
+	return NewGeneratorWithFS(lang, DiskFS{})
+}
+
+// NewGeneratorWithFS creates a generator for the specified language that writes to out
+func NewGeneratorWithFS(lang string, out OutputFS) (Generator, error) {
 	switch lang {
 	case "go":
-		return &GoGenerator{}, nil
+		return &GoGenerator{out: out}, nil
 	case "node":
-		return &NodeGenerator{}, nil
+		return &NodeGenerator{out: out}, nil
 	default:
 		return nil, fmt.Errorf("unsupported language type: %s", lang)
 	}
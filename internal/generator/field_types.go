@@ -0,0 +1,359 @@
+package generator
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// FieldType describes how a model field type is declared, stored, edited and
+// listed in a generated project. Parse, FormValue, Text and Cell are text/template snippets
+// rendered once per field at generation time (see FieldTypeSnippetData).
+type FieldType struct {
+	Name       string   // config name: "decimal"
+	GoType     string   // Go type in the model: "decimal.Decimal"
+	Imports    []string // imports the Go type needs in models/ and handlers/
+	GoModDeps  []string // go.mod requirements: "github.com/shopspring/decimal v1.4.0"
+	GormTag    string   // default GORM tag, skipped if the user sets the same key
+	InputType  string   // text, number, checkbox, textarea, select, date, datetime-local, file
+	Step       string   // step attribute for number inputs
+	Searchable bool     // included in the list page LIKE search
+	Filter     string   // query.Kind of list filters and sorting: Text, Number, Bool, Time; "" excludes the field
+	Numeric    bool     // accepts min/max validation
+	Textual    bool     // accepts length, pattern and email validation
+	Parse      string   // handler code assigning the form value to the field; reports errors via errs.add
+	ParseDeps  []string // extra handler imports used by Parse
+	FormValue  string   // runtime template expression for the edit form value
+	Text       string   // handler expression formatting the field as form text, for CSV and Excel export; "" uses fmt.Sprint
+	Cell       string   // runtime template expression for the list cell
+	SQLTypes   SQLTypes // column type per dialect for SQL migrations
+	Size       int      // default size substituted for %d in SQLTypes
+}
+
+// SQLTypes maps a dialect to its column type; "%d" is replaced by the size
+type SQLTypes map[DBType]string
+
+var (
+	sqlVarchar = SQLTypes{DBTypeSQLite: "TEXT", DBTypePostgres: "VARCHAR(%d)", DBTypeMySQL: "VARCHAR(%d)", DBTypeMSSQL: "NVARCHAR(%d)"}
+	sqlText    = SQLTypes{DBTypeSQLite: "TEXT", DBTypePostgres: "TEXT", DBTypeMySQL: "LONGTEXT", DBTypeMSSQL: "NVARCHAR(MAX)"}
+	sqlInt     = SQLTypes{DBTypeSQLite: "INTEGER", DBTypePostgres: "BIGINT", DBTypeMySQL: "BIGINT", DBTypeMSSQL: "BIGINT"}
+	sqlUint    = SQLTypes{DBTypeSQLite: "INTEGER", DBTypePostgres: "BIGINT", DBTypeMySQL: "BIGINT UNSIGNED", DBTypeMSSQL: "BIGINT"}
+	sqlTime    = SQLTypes{DBTypeSQLite: "DATETIME", DBTypePostgres: "TIMESTAMPTZ", DBTypeMySQL: "DATETIME(3)", DBTypeMSSQL: "DATETIMEOFFSET"}
+)
+
+// FieldTypeSnippetData is passed to the Parse, FormValue, Text and Cell snippets
+type FieldTypeSnippetData struct {
+	Field  string   // Go field name: "Price"
+	Form   string   // form input name: "price"
+	Values []string // declared enum values
+	Model  string   // snake_case model name, for routes: "product"
+}
+
+var (
+	fieldTypesMu sync.RWMutex
+	fieldTypes   = map[string]FieldType{}
+)
+
+// RegisterFieldType adds or replaces a field type in the registry
+func RegisterFieldType(ft FieldType) {
+	fieldTypesMu.Lock()
+	defer fieldTypesMu.Unlock()
+	fieldTypes[ft.Name] = ft
+}
+
+// LookupFieldType returns the registered field type with the given name
+func LookupFieldType(name string) (FieldType, bool) {
+	fieldTypesMu.RLock()
+	defer fieldTypesMu.RUnlock()
+	ft, ok := fieldTypes[name]
+	return ft, ok
+}
+
+// FieldTypeNames returns the registered type names in sorted order
+func FieldTypeNames() []string {
+	fieldTypesMu.RLock()
+	defer fieldTypesMu.RUnlock()
+	names := make([]string, 0, len(fieldTypes))
+	for name := range fieldTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+const (
+	parseString = `item.{{.Field}} = r.FormValue("{{.Form}}")`
+	cellPlain   = `{{"{{"}}.{{.Field}}{{"}}"}}`
+	valuePlain  = `{{"{{"}}.Item.{{.Field}}{{"}}"}}`
+	textPlain   = `item.{{.Field}}`
+
+	// downloadURL is the route serving a file field of the list row's record
+	downloadURL = `/{{.Model}}s/{{"{{"}}.ID{{"}}"}}/files/{{.Form}}`
+)
+
+// storageDeps are the go.mod requirements of the generated storage package
+// and its test against an in-process S3 fake
+var storageDeps = []string{
+	"github.com/minio/minio-go/v7 v7.0.80",
+	"github.com/johannesboyne/gofakes3 v0.0.0-20240701191259-edd0227ffc37",
+}
+
+// uploadParse is the Parse snippet of file fields; image uploads must be
+// images and get a thumbnail
+func uploadParse(image bool) string {
+	return `if key, err := upload(r, h.files, "{{.Model}}", "{{.Form}}", ` + strconv.FormatBool(image) + `); err != nil {
+	errs.add("{{.Form}}", uploadMessage(err))
+} else if key != "" {
+	item.{{.Field}} = key
+}`
+}
+
+func init() {
+	builtin := []FieldType{
+		{
+			Name: "string", GoType: "string", InputType: "text", Searchable: true, Textual: true, Filter: "Text",
+			SQLTypes: sqlVarchar, Size: 255,
+			Parse: parseString, FormValue: valuePlain, Cell: cellPlain,
+			Text: textPlain,
+		},
+		{
+			Name: "text", GoType: "string", GormTag: "type:text", InputType: "textarea", Searchable: true, Textual: true, Filter: "Text",
+			SQLTypes: sqlText,
+			Parse:    parseString, FormValue: valuePlain, Cell: cellPlain,
+			Text: textPlain,
+		},
+		{
+			Name: "int", GoType: "int", InputType: "number", Numeric: true, Filter: "Number",
+			SQLTypes: sqlInt,
+			Parse: `if s := r.FormValue("{{.Form}}"); s != "" {
+	if v, err := strconv.Atoi(s); err == nil {
+		item.{{.Field}} = v
+	} else {
+		errs.add("{{.Form}}", "정수를 입력하세요")
+	}
+}`,
+			Text:      `strconv.Itoa(item.{{.Field}})`,
+			FormValue: valuePlain, Cell: cellPlain,
+		},
+		{
+			Name: "uint", GoType: "uint", InputType: "number", Numeric: true, Filter: "Number",
+			SQLTypes: sqlUint,
+			Parse: `if s := r.FormValue("{{.Form}}"); s != "" {
+	if v, err := strconv.ParseUint(s, 10, 64); err == nil {
+		item.{{.Field}} = uint(v)
+	} else {
+		errs.add("{{.Form}}", "0 이상의 정수를 입력하세요")
+	}
+}`,
+			Text:      `strconv.FormatUint(uint64(item.{{.Field}}), 10)`,
+			FormValue: valuePlain, Cell: cellPlain,
+		},
+		{
+			Name: "float64", GoType: "float64", InputType: "number", Step: "0.01", Numeric: true, Filter: "Number",
+			SQLTypes: SQLTypes{DBTypeSQLite: "REAL", DBTypePostgres: "DOUBLE PRECISION", DBTypeMySQL: "DOUBLE", DBTypeMSSQL: "FLOAT"},
+			Parse: `if s := r.FormValue("{{.Form}}"); s != "" {
+	if v, err := strconv.ParseFloat(s, 64); err == nil {
+		item.{{.Field}} = v
+	} else {
+		errs.add("{{.Form}}", "숫자를 입력하세요")
+	}
+}`,
+			Text:      `strconv.FormatFloat(item.{{.Field}}, 'f', -1, 64)`,
+			FormValue: valuePlain, Cell: cellPlain,
+		},
+		{
+			Name: "decimal", GoType: "decimal.Decimal", Filter: "Number",
+			SQLTypes:  SQLTypes{DBTypeSQLite: "DECIMAL(18,2)", DBTypePostgres: "NUMERIC(18,2)", DBTypeMySQL: "DECIMAL(18,2)", DBTypeMSSQL: "DECIMAL(18,2)"},
+			Imports:   []string{"github.com/shopspring/decimal"},
+			GoModDeps: []string{"github.com/shopspring/decimal v1.4.0"},
+			GormTag:   "type:decimal(18,2)", InputType: "number", Step: "0.01", Numeric: true,
+			Parse: `if s := r.FormValue("{{.Form}}"); s != "" {
+	if v, err := decimal.NewFromString(s); err == nil {
+		item.{{.Field}} = v
+	} else {
+		errs.add("{{.Form}}", "숫자를 입력하세요")
+	}
+}`,
+			Text:      `item.{{.Field}}.String()`,
+			FormValue: `{{"{{"}}.Item.{{.Field}}.StringFixed 2{{"}}"}}`,
+			Cell:      `{{"{{"}}.{{.Field}}.StringFixed 2{{"}}"}}`,
+		},
+		{
+			Name: "bool", GoType: "bool", InputType: "checkbox", Filter: "Bool",
+			SQLTypes:  SQLTypes{DBTypeSQLite: "BOOLEAN", DBTypePostgres: "BOOLEAN", DBTypeMySQL: "BOOLEAN", DBTypeMSSQL: "BIT"},
+			Parse:     `item.{{.Field}} = r.FormValue("{{.Form}}") == "true" || r.FormValue("{{.Form}}") == "on"`,
+			Text:      `strconv.FormatBool(item.{{.Field}})`,
+			FormValue: valuePlain, Cell: cellPlain,
+		},
+		{
+			Name: "time.Time", GoType: "time.Time", Imports: []string{"time"}, InputType: "datetime-local", Filter: "Time",
+			SQLTypes: sqlTime,
+			Parse: `if s := r.FormValue("{{.Form}}"); s != "" {
+	if v, err := time.ParseInLocation("2006-01-02T15:04", s, time.Local); err == nil {
+		item.{{.Field}} = v
+	} else if v, err := time.Parse(time.RFC3339, s); err == nil {
+		item.{{.Field}} = v // API clients send RFC 3339
+	} else {
+		errs.add("{{.Form}}", "날짜/시간 형식이 올바르지 않습니다")
+	}
+}`,
+			Text:      `timeText(item.{{.Field}}, "2006-01-02T15:04")`,
+			FormValue: `{{"{{"}}.Item.{{.Field}}.Format "2006-01-02T15:04"{{"}}"}}`,
+			Cell:      `{{"{{"}}.{{.Field}}.Format "2006-01-02 15:04"{{"}}"}}`,
+		},
+		{
+			Name: "date", GoType: "time.Time", Imports: []string{"time"}, GormTag: "type:date", InputType: "date", Filter: "Time",
+			SQLTypes: SQLTypes{DBTypeSQLite: "DATE", DBTypePostgres: "DATE", DBTypeMySQL: "DATE", DBTypeMSSQL: "DATE"},
+			Parse: `if s := r.FormValue("{{.Form}}"); s != "" {
+	if v, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		item.{{.Field}} = v
+	} else if v, err := time.Parse(time.RFC3339, s); err == nil {
+		item.{{.Field}} = v // API clients send RFC 3339
+	} else {
+		errs.add("{{.Form}}", "날짜 형식이 올바르지 않습니다")
+	}
+}`,
+			Text:      `timeText(item.{{.Field}}, "2006-01-02")`,
+			FormValue: `{{"{{"}}.Item.{{.Field}}.Format "2006-01-02"{{"}}"}}`,
+			Cell:      `{{"{{"}}.{{.Field}}.Format "2006-01-02"{{"}}"}}`,
+		},
+		{
+			Name: "enum", GoType: "string", GormTag: "size:64", InputType: "select", Searchable: true, Filter: "Text",
+			SQLTypes: sqlVarchar, Size: 64,
+			Parse: `switch v := r.FormValue("{{.Form}}"); v {
+case {{range $i, $v := .Values}}{{if $i}}, {{end}}{{printf "%q" $v}}{{end}}:
+	item.{{.Field}} = v
+case "":
+default:
+	errs.add("{{.Form}}", "허용되지 않는 값입니다")
+}`,
+			Text:      textPlain,
+			FormValue: valuePlain, Cell: cellPlain,
+		},
+		{
+			Name: "uuid", GoType: "uuid.UUID", Filter: "Text",
+			SQLTypes: sqlVarchar, Size: 36,
+			Imports:   []string{"github.com/google/uuid"},
+			GoModDeps: []string{"github.com/google/uuid v1.6.0"},
+			GormTag:   "size:36", InputType: "text",
+			Parse: `if s := r.FormValue("{{.Form}}"); s != "" {
+	if v, err := uuid.Parse(s); err == nil {
+		item.{{.Field}} = v
+	} else {
+		errs.add("{{.Form}}", "UUID 형식이 올바르지 않습니다")
+	}
+}
+if item.{{.Field}} == uuid.Nil {
+	item.{{.Field}} = uuid.New()
+}`,
+			Text:      `item.{{.Field}}.String()`,
+			FormValue: valuePlain, Cell: cellPlain,
+		},
+		{
+			Name: "json", GoType: "datatypes.JSON",
+			SQLTypes:  SQLTypes{DBTypeSQLite: "JSON", DBTypePostgres: "JSONB", DBTypeMySQL: "JSON", DBTypeMSSQL: "NVARCHAR(MAX)"},
+			Imports:   []string{"gorm.io/datatypes"},
+			GoModDeps: []string{"gorm.io/datatypes v1.2.5"},
+			InputType: "textarea",
+			Parse: `if v := strings.TrimSpace(r.FormValue("{{.Form}}")); v == "" {
+	item.{{.Field}} = nil
+} else if json.Valid([]byte(v)) {
+	item.{{.Field}} = datatypes.JSON(v)
+} else {
+	errs.add("{{.Form}}", "올바른 JSON이 아닙니다")
+}`,
+			ParseDeps: []string{"encoding/json"},
+			Text:      `string(item.{{.Field}})`,
+			FormValue: `{{"{{"}}printf "%s" .Item.{{.Field}}{{"}}"}}`,
+			Cell:      `<code class="text-xs">{{"{{"}}printf "%.40s" .{{.Field}}{{"}}"}}</code>`,
+		},
+		{
+			Name: "file", GoType: "string", GormTag: "size:512", InputType: "file", Filter: "Text",
+			SQLTypes: sqlVarchar, Size: 512, GoModDeps: storageDeps,
+			Parse:     uploadParse(false),
+			Text:      textPlain,
+			FormValue: valuePlain,
+			Cell:      `{{"{{"}}if .{{.Field}}{{"}}"}}<a href="` + downloadURL + `" class="link link-hover" target="_blank">파일</a>{{"{{"}}end{{"}}"}}`,
+		},
+		{
+			Name: "image", GoType: "string", GormTag: "size:512", InputType: "file", Filter: "Text",
+			SQLTypes: sqlVarchar, Size: 512, GoModDeps: append([]string{"golang.org/x/image v0.18.0"}, storageDeps...),
+			Parse:     uploadParse(true),
+			Text:      textPlain,
+			FormValue: valuePlain,
+			Cell: `{{"{{"}}if .{{.Field}}{{"}}"}}<a href="` + downloadURL + `" target="_blank">` +
+				`<img src="` + downloadURL + `/thumb" alt="" class="h-10 w-10 rounded object-cover" loading="lazy" /></a>{{"{{"}}end{{"}}"}}`,
+		},
+	}
+	for _, ft := range builtin {
+		RegisterFieldType(ft)
+	}
+}
+
+// renderSnippet executes a field type snippet for one field
+func renderSnippet(snippet string, data FieldTypeSnippetData) string {
+	if snippet == "" {
+		return ""
+	}
+	t, err := template.New("snippet").Parse(snippet)
+	if err != nil {
+		return "// invalid snippet: " + err.Error()
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "// invalid snippet: " + err.Error()
+	}
+	return buf.String()
+}
+
+// indent prefixes every line of code with prefix
+func indent(code, prefix string) string {
+	if code == "" {
+		return ""
+	}
+	return prefix + strings.ReplaceAll(code, "\n", "\n"+prefix)
+}
+
+// mergeGormTags prepends the type's default tag unless the user already set
+// the same key (e.g. a custom "type:" or "size:")
+func mergeGormTags(defaultTag string, tags []string) string {
+	joined := joinGormTags(tags)
+	if defaultTag == "" {
+		return joined
+	}
+	key := defaultTag
+	if i := strings.Index(key, ":"); i >= 0 {
+		key = key[:i+1]
+	}
+	for _, t := range tags {
+		if strings.HasPrefix(strings.ToLower(strings.TrimSpace(t)), strings.ToLower(key)) {
+			return joined
+		}
+	}
+	if joined == "" {
+		return defaultTag
+	}
+	return defaultTag + ";" + joined
+}
+
+// splitImports separates standard library imports from third-party ones
+func splitImports(imports []string) (std, third []string) {
+	seen := map[string]bool{}
+	for _, imp := range imports {
+		if seen[imp] {
+			continue
+		}
+		seen[imp] = true
+		if strings.Contains(strings.SplitN(imp, "/", 2)[0], ".") {
+			third = append(third, imp)
+		} else {
+			std = append(std, imp)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(third)
+	return std, third
+}
@@ -0,0 +1,150 @@
+package generator
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+)
+
+// validationCode renders the handler checks for a field's validation rules.
+// The code runs after the form was parsed into item and reports through errs.
+func validationCode(model string, f FieldTmplData, v *FieldValidation) string {
+	if v == nil || f.IsID {
+		return ""
+	}
+	var b strings.Builder
+	field := "item." + f.Name
+	raw := fmt.Sprintf("r.FormValue(%q)", f.JsonName)
+	add := func(cond, msg string) {
+		fmt.Fprintf(&b, "if %s {\n\terrs.add(%q, %q)\n}\n", cond, f.JsonName, msg)
+	}
+
+	if v.Required {
+		if f.InputType == "file" {
+			add(field+` == ""`, "파일을 선택하세요")
+		} else {
+			add("strings.TrimSpace("+raw+`) == ""`, "필수 항목입니다")
+		}
+	}
+
+	if v.Min != nil {
+		add(raw+` != "" && `+numberLess(f, field, *v.Min), formatNumber(*v.Min)+" 이상이어야 합니다")
+	}
+	if v.Max != nil {
+		add(raw+` != "" && `+numberGreater(f, field, *v.Max), formatNumber(*v.Max)+" 이하여야 합니다")
+	}
+
+	if v.MinLength > 0 {
+		add(fmt.Sprintf(`%s != "" && utf8.RuneCountInString(%s) < %d`, field, field, v.MinLength),
+			fmt.Sprintf("%d자 이상 입력하세요", v.MinLength))
+	}
+	if v.MaxLength > 0 {
+		add(fmt.Sprintf(`utf8.RuneCountInString(%s) > %d`, field, v.MaxLength),
+			fmt.Sprintf("%d자 이하로 입력하세요", v.MaxLength))
+	}
+	if v.Pattern != "" {
+		add(fmt.Sprintf(`%s != "" && !%s.MatchString(%s)`, field, patternVar(model, f.Name), field), "형식이 올바르지 않습니다")
+	}
+	if v.Email {
+		fmt.Fprintf(&b, "if %s != \"\" {\n\tif a, err := mail.ParseAddress(%s); err != nil || a.Address != %s {\n\t\terrs.add(%q, %q)\n\t}\n}\n",
+			field, field, field, f.JsonName, "올바른 이메일 주소가 아닙니다")
+	}
+
+	if v.Unique {
+		// Unscoped: records in the trash of soft-delete models keep their values
+		fmt.Fprintf(&b, `if %[1]s != "" {
+	var n int64
+	h.db.Unscoped().Model(&models.%[2]s{}).Where(h.db.NamingStrategy.ColumnName("", %[3]q)+" = ?", %[4]s).Where("id <> ?", item.ID).Count(&n)
+	if n > 0 {
+		errs.add(%[5]q, msgTaken)
+	}
+}
+`, raw, model, f.Name, field, f.JsonName)
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// validationImports lists the handler imports validationCode needs
+func validationImports(v *FieldValidation) []string {
+	if v == nil {
+		return nil
+	}
+	var imports []string
+	if v.MinLength > 0 || v.MaxLength > 0 {
+		imports = append(imports, "unicode/utf8")
+	}
+	if v.Pattern != "" {
+		imports = append(imports, "regexp")
+	}
+	if v.Email {
+		imports = append(imports, "net/mail")
+	}
+	return imports
+}
+
+// validationAttrs renders the HTML5 attributes matching a field's rules
+func validationAttrs(f FieldTmplData, v *FieldValidation) string {
+	if v == nil {
+		return ""
+	}
+	var attrs []string
+	if v.Required {
+		if f.InputType == "file" {
+			// keep the current file when editing
+			attrs = append(attrs, `{{if not .IsEdit}}required{{end}}`)
+		} else {
+			attrs = append(attrs, "required")
+		}
+	}
+	if f.InputType == "number" {
+		if v.Min != nil {
+			attrs = append(attrs, fmt.Sprintf(`min="%s"`, formatNumber(*v.Min)))
+		}
+		if v.Max != nil {
+			attrs = append(attrs, fmt.Sprintf(`max="%s"`, formatNumber(*v.Max)))
+		}
+	}
+	if v.MinLength > 0 {
+		attrs = append(attrs, fmt.Sprintf(`minlength="%d"`, v.MinLength))
+	}
+	if v.MaxLength > 0 {
+		attrs = append(attrs, fmt.Sprintf(`maxlength="%d"`, v.MaxLength))
+	}
+	if v.Pattern != "" && f.InputType != "textarea" {
+		attrs = append(attrs, fmt.Sprintf(`pattern="%s"`, html.EscapeString(v.Pattern)))
+	}
+	if len(attrs) == 0 {
+		return ""
+	}
+	return " " + strings.Join(attrs, " ")
+}
+
+// patternVar names the package-level regexp generated for a field
+func patternVar(model, field string) string {
+	return lowerFirst(model) + field + "Pattern"
+}
+
+// anchoredPattern matches the whole value, like the HTML pattern attribute
+func anchoredPattern(pattern string) string {
+	return "^(?:" + pattern + ")$"
+}
+
+func numberLess(f FieldTmplData, field string, n float64) string {
+	if f.Kind == "decimal" {
+		return fmt.Sprintf("%s.LessThan(decimal.RequireFromString(%q))", field, formatNumber(n))
+	}
+	return fmt.Sprintf("float64(%s) < %s", field, formatNumber(n))
+}
+
+func numberGreater(f FieldTmplData, field string, n float64) string {
+	if f.Kind == "decimal" {
+		return fmt.Sprintf("%s.GreaterThan(decimal.RequireFromString(%q))", field, formatNumber(n))
+	}
+	return fmt.Sprintf("float64(%s) > %s", field, formatNumber(n))
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
@@ -1,7 +1,6 @@
 package generator
 
 import (
-	"os"
 	"path/filepath"
 	"strings"
 
@@ -10,7 +9,9 @@ import (
 )
 
 // GoGenerator implements the Generator interface for Go projects
-type GoGenerator struct{}
+type GoGenerator struct {
+	out OutputFS // nil means disk
+}
 
 func (g *GoGenerator) Scaffold(path string) error {
 	dirs := []string{
@@ -19,7 +20,7 @@ func (g *GoGenerator) Scaffold(path string) error {
 		filepath.Join(path, "assets"),
 	}
 	for _, dir := range dirs {
-		if err := os.MkdirAll(dir, 0755); err != nil {
+		if err := outputOrDisk(g.out).MkdirAll(dir, 0755); err != nil {
 			return err
 		}
 	}
@@ -33,11 +34,15 @@ func (g *GoGenerator) scaffoldGorm(path string) error {
 		filepath.Join(path, "models"),
 		filepath.Join(path, "handlers"),
 		filepath.Join(path, "middleware"),
+		filepath.Join(path, "query"),
+		filepath.Join(path, "storage"),
+		filepath.Join(path, "mail"),
 		filepath.Join(path, "templates"),
 		filepath.Join(path, "assets"),
+		filepath.Join(path, "migrations"),
 	}
 	for _, dir := range dirs {
-		if err := os.MkdirAll(dir, 0755); err != nil {
+		if err := outputOrDisk(g.out).MkdirAll(dir, 0755); err != nil {
 			return err
 		}
 	}
@@ -49,7 +54,7 @@ func (g *GoGenerator) CreateManifest(config ProjectConfig) error {
 		return nil // GormCodeGenerator handles go.mod via template
 	}
 	goMod := strings.Replace(templates.GoModTemplate(), "{{PROJECT_NAME}}", config.ProjectName, 1)
-	return os.WriteFile(filepath.Join(config.TargetPath, "go.mod"), []byte(goMod), 0644)
+	return outputOrDisk(g.out).WriteFile(filepath.Join(config.TargetPath, "go.mod"), []byte(goMod), 0644)
 }
 
 func (g *GoGenerator) GenerateCode(config ProjectConfig) error {
@@ -57,7 +62,7 @@ func (g *GoGenerator) GenerateCode(config ProjectConfig) error {
 		if err := g.scaffoldGorm(config.TargetPath); err != nil {
 			return err
 		}
-		return (&GormCodeGenerator{}).Generate(config)
+		return (&GormCodeGenerator{out: g.out}).Generate(config)
 	}
 
 	// Legacy: Replace template variables
@@ -72,7 +77,7 @@ func (g *GoGenerator) GenerateCode(config ProjectConfig) error {
 
 	// Read go.mod for potential module injection
 	goModPath := filepath.Join(config.TargetPath, "go.mod")
-	goModBytes, err := os.ReadFile(goModPath)
+	goModBytes, err := outputOrDisk(g.out).ReadFile(goModPath)
 	if err != nil {
 		return err
 	}
@@ -95,13 +100,13 @@ func (g *GoGenerator) GenerateCode(config ProjectConfig) error {
 	}
 
 	// Write files
-	if err := os.WriteFile(filepath.Join(config.TargetPath, "main.go"), []byte(mainGo), 0644); err != nil {
+	if err := outputOrDisk(g.out).WriteFile(filepath.Join(config.TargetPath, "main.go"), []byte(mainGo), 0644); err != nil {
 		return err
 	}
-	if err := os.WriteFile(filepath.Join(config.TargetPath, "templates", "index.html"), []byte(indexHTML), 0644); err != nil {
+	if err := outputOrDisk(g.out).WriteFile(filepath.Join(config.TargetPath, "templates", "index.html"), []byte(indexHTML), 0644); err != nil {
 		return err
 	}
-	if err := os.WriteFile(goModPath, []byte(goMod), 0644); err != nil {
+	if err := outputOrDisk(g.out).WriteFile(goModPath, []byte(goMod), 0644); err != nil {
 		return err
 	}
 
@@ -109,14 +114,14 @@ func (g *GoGenerator) GenerateCode(config ProjectConfig) error {
 }
 
 // ScaffoldGorm creates the directory structure for a GORM project
-func ScaffoldGorm(path string) error {
-	return (&GoGenerator{}).scaffoldGorm(path)
+func ScaffoldGorm(out OutputFS, path string) error {
+	return (&GoGenerator{out: out}).scaffoldGorm(path)
 }
 
 // GenerateLegacyCode generates legacy (non-GORM) Go project files with module injection.
 // This is the code generation portion only — scaffold and manifest must be done separately.
-func GenerateLegacyCode(config ProjectConfig) error {
-	return (&GoGenerator{}).GenerateCode(config)
+func GenerateLegacyCode(out OutputFS, config ProjectConfig) error {
+	return (&GoGenerator{out: out}).GenerateCode(config)
 }
 
 func filterActiveModules(selectedIDs []string) []modules.ModuleDef {
@@ -2,7 +2,6 @@ package generator
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
@@ -11,7 +10,9 @@ import (
 )
 
 // GormCodeGenerator generates multi-file GORM-based Go projects
-type GormCodeGenerator struct{}
+type GormCodeGenerator struct {
+	out OutputFS // nil means disk
+}
 
 // Generate creates a full GORM project from config
 func (g *GormCodeGenerator) Generate(config ProjectConfig) error {
@@ -35,6 +36,27 @@ func (g *GormCodeGenerator) Generate(config ProjectConfig) error {
 	if err := g.renderGoFile(filepath.Join(config.TargetPath, "handlers"), "helpers.go", "helpers.go.tmpl", data); err != nil {
 		return fmt.Errorf("helpers.go: %w", err)
 	}
+	if err := g.renderGoFile(filepath.Join(config.TargetPath, "handlers"), "export_test.go", "export_test.go.tmpl", data); err != nil {
+		return fmt.Errorf("export tests: %w", err)
+	}
+	if err := g.renderGoFile(filepath.Join(config.TargetPath, "handlers"), "list_test.go", "list_test.go.tmpl", data); err != nil {
+		return fmt.Errorf("list tests: %w", err)
+	}
+	// List filter, sort and page parameters
+	if err := g.renderGoFile(filepath.Join(config.TargetPath, "query"), "query.go", "query.go.tmpl", data); err != nil {
+		return fmt.Errorf("query.go: %w", err)
+	}
+	if err := g.renderGoFile(filepath.Join(config.TargetPath, "query"), "query_test.go", "query_test.go.tmpl", data); err != nil {
+		return fmt.Errorf("query tests: %w", err)
+	}
+	// Upload store of file and image fields
+	if err := g.renderStorage(config.TargetPath, data); err != nil {
+		return fmt.Errorf("storage: %w", err)
+	}
+	// API description served at /openapi.json and /docs
+	if err := g.renderOpenAPI(config.TargetPath, data); err != nil {
+		return fmt.Errorf("openapi.json: %w", err)
+	}
 	// HTML templates (use << >> delimiters so {{ }} passes through to output)
 	if err := g.renderHTMLFile(filepath.Join(config.TargetPath, "templates"), "layout.html", "layout.html.tmpl", data); err != nil {
 		return fmt.Errorf("layout.html: %w", err)
@@ -62,15 +84,27 @@ func (g *GormCodeGenerator) Generate(config ProjectConfig) error {
 			return fmt.Errorf("handler %s: %w", model.Name, err)
 		}
 
-		listFile := strings.ToLower(model.Name) + "_list.html"
+		listFile := model.NameSnake + "_list.html"
 		if err := g.renderHTMLFile(filepath.Join(config.TargetPath, "templates"), listFile, "list.html.tmpl", modelData); err != nil {
 			return fmt.Errorf("list template %s: %w", model.Name, err)
 		}
 
-		formFile := strings.ToLower(model.Name) + "_form.html"
+		formFile := model.NameSnake + "_form.html"
 		if err := g.renderHTMLFile(filepath.Join(config.TargetPath, "templates"), formFile, "form.html.tmpl", modelData); err != nil {
 			return fmt.Errorf("form template %s: %w", model.Name, err)
 		}
+
+		importFile := model.NameSnake + "_import.html"
+		if err := g.renderHTMLFile(filepath.Join(config.TargetPath, "templates"), importFile, "import.html.tmpl", modelData); err != nil {
+			return fmt.Errorf("import template %s: %w", model.Name, err)
+		}
+	}
+
+	// Audit log model
+	if data.HasAudit {
+		if err := g.renderGoFile(filepath.Join(config.TargetPath, "models"), "audit_log.go", "audit_log_model.go.tmpl", data); err != nil {
+			return fmt.Errorf("audit log model: %w", err)
+		}
 	}
 
 	// RBAC templates (Phase 2)
@@ -79,15 +113,69 @@ func (g *GormCodeGenerator) Generate(config ProjectConfig) error {
 		if err := g.renderGoFile(filepath.Join(config.TargetPath, "models"), "user.go", "user_model.go.tmpl", data); err != nil {
 			return fmt.Errorf("user model: %w", err)
 		}
+		if err := g.renderGoFile(filepath.Join(config.TargetPath, "models"), "password_reset.go", "password_reset_model.go.tmpl", data); err != nil {
+			return fmt.Errorf("password reset model: %w", err)
+		}
+		if err := g.renderGoFile(filepath.Join(config.TargetPath, "models"), "session.go", "session_model.go.tmpl", data); err != nil {
+			return fmt.Errorf("session model: %w", err)
+		}
+		// Mailer of the password reset links
+		if err := g.renderMail(config.TargetPath, data); err != nil {
+			return fmt.Errorf("mail: %w", err)
+		}
 		if err := g.renderGoFile(filepath.Join(config.TargetPath, "middleware"), "auth.go", "middleware_auth.go.tmpl", data); err != nil {
 			return fmt.Errorf("middleware auth: %w", err)
 		}
 		if err := g.renderGoFile(filepath.Join(config.TargetPath, "middleware"), "rbac.go", "middleware_rbac.go.tmpl", data); err != nil {
 			return fmt.Errorf("middleware rbac: %w", err)
 		}
+		if err := g.renderGoFile(filepath.Join(config.TargetPath, "middleware"), "csrf.go", "middleware_csrf.go.tmpl", data); err != nil {
+			return fmt.Errorf("middleware csrf: %w", err)
+		}
+		if err := g.renderGoFile(filepath.Join(config.TargetPath, "middleware"), "ratelimit.go", "middleware_ratelimit.go.tmpl", data); err != nil {
+			return fmt.Errorf("middleware rate limit: %w", err)
+		}
+		if err := g.renderGoFile(config.TargetPath, "main_test.go", "main_test.go.tmpl", data); err != nil {
+			return fmt.Errorf("test helpers: %w", err)
+		}
+		if err := g.renderGoFile(config.TargetPath, "permissions_test.go", "permissions_test.go.tmpl", data); err != nil {
+			return fmt.Errorf("permission tests: %w", err)
+		}
+		if err := g.renderGoFile(config.TargetPath, "password_reset_test.go", "password_reset_test.go.tmpl", data); err != nil {
+			return fmt.Errorf("password reset tests: %w", err)
+		}
+		if err := g.renderGoFile(config.TargetPath, "sessions_test.go", "sessions_test.go.tmpl", data); err != nil {
+			return fmt.Errorf("session tests: %w", err)
+		}
+		if err := g.renderGoFile(config.TargetPath, "csrf_test.go", "csrf_test.go.tmpl", data); err != nil {
+			return fmt.Errorf("csrf tests: %w", err)
+		}
+		if err := g.renderGoFile(config.TargetPath, "auth_test.go", "auth_test.go.tmpl", data); err != nil {
+			return fmt.Errorf("auth tests: %w", err)
+		}
+		if data.HasOwners || data.HasTenants {
+			if err := g.renderGoFile(config.TargetPath, "scoping_test.go", "scoping_test.go.tmpl", data); err != nil {
+				return fmt.Errorf("scoping tests: %w", err)
+			}
+		}
 		if err := g.renderGoFile(filepath.Join(config.TargetPath, "handlers"), "auth.go", "auth_handler.go.tmpl", data); err != nil {
 			return fmt.Errorf("auth handler: %w", err)
 		}
+		if err := g.renderGoFile(filepath.Join(config.TargetPath, "handlers"), "sessions.go", "session_handler.go.tmpl", data); err != nil {
+			return fmt.Errorf("session handler: %w", err)
+		}
+		if err := g.renderGoFile(filepath.Join(config.TargetPath, "handlers"), "login_throttle.go", "login_throttle.go.tmpl", data); err != nil {
+			return fmt.Errorf("login throttle: %w", err)
+		}
+		if err := g.renderGoFile(filepath.Join(config.TargetPath, "handlers"), "users.go", "users_handler.go.tmpl", data); err != nil {
+			return fmt.Errorf("users handler: %w", err)
+		}
+		if err := g.renderHTMLFile(filepath.Join(config.TargetPath, "templates"), "users.html", "users.html.tmpl", data); err != nil {
+			return fmt.Errorf("users template: %w", err)
+		}
+		if err := g.renderTwoFactor(config.TargetPath, data); err != nil {
+			return fmt.Errorf("two-factor authentication: %w", err)
+		}
 		if err := g.renderHTMLFile(filepath.Join(config.TargetPath, "templates"), "login.html", "login.html.tmpl", data); err != nil {
 			return fmt.Errorf("login template: %w", err)
 		}
@@ -97,14 +185,22 @@ func (g *GormCodeGenerator) Generate(config ProjectConfig) error {
 		if err := g.renderHTMLFile(filepath.Join(config.TargetPath, "templates"), "forgot_password.html", "forgot_password.html.tmpl", data); err != nil {
 			return fmt.Errorf("forgot_password template: %w", err)
 		}
+		if err := g.renderHTMLFile(filepath.Join(config.TargetPath, "templates"), "reset_password.html", "reset_password.html.tmpl", data); err != nil {
+			return fmt.Errorf("reset_password template: %w", err)
+		}
+	}
+
+	// SQL migrations + runner
+	if err := RenderMigrations(g.out, config); err != nil {
+		return fmt.Errorf("migrations: %w", err)
 	}
 
 	return nil
 }
 
 // RenderModels generates GORM model files (models/*.go)
-func RenderModels(config ProjectConfig) error {
-	g := &GormCodeGenerator{}
+func RenderModels(out OutputFS, config ProjectConfig) error {
+	g := &GormCodeGenerator{out: out}
 	data := buildTemplateData(config)
 
 	for _, model := range data.Models {
@@ -118,12 +214,17 @@ func RenderModels(config ProjectConfig) error {
 			return fmt.Errorf("model %s: %w", model.Name, err)
 		}
 	}
+	if data.HasAudit {
+		if err := g.renderGoFile(filepath.Join(config.TargetPath, "models"), "audit_log.go", "audit_log_model.go.tmpl", data); err != nil {
+			return fmt.Errorf("audit log model: %w", err)
+		}
+	}
 	return nil
 }
 
 // RenderHandlers generates handler files (handlers/*.go + helpers.go)
-func RenderHandlers(config ProjectConfig) error {
-	g := &GormCodeGenerator{}
+func RenderHandlers(out OutputFS, config ProjectConfig) error {
+	g := &GormCodeGenerator{out: out}
 	data := buildTemplateData(config)
 	if config.Port > 0 {
 		data.Port = fmt.Sprintf("%d", config.Port)
@@ -142,10 +243,34 @@ func RenderHandlers(config ProjectConfig) error {
 	if err := g.renderGoFile(filepath.Join(config.TargetPath, "handlers"), "helpers.go", "helpers.go.tmpl", data); err != nil {
 		return fmt.Errorf("helpers.go: %w", err)
 	}
+	if err := g.renderGoFile(filepath.Join(config.TargetPath, "handlers"), "export_test.go", "export_test.go.tmpl", data); err != nil {
+		return fmt.Errorf("export tests: %w", err)
+	}
+	if err := g.renderGoFile(filepath.Join(config.TargetPath, "handlers"), "list_test.go", "list_test.go.tmpl", data); err != nil {
+		return fmt.Errorf("list tests: %w", err)
+	}
+	// List filter, sort and page parameters
+	if err := g.renderGoFile(filepath.Join(config.TargetPath, "query"), "query.go", "query.go.tmpl", data); err != nil {
+		return fmt.Errorf("query.go: %w", err)
+	}
+	if err := g.renderGoFile(filepath.Join(config.TargetPath, "query"), "query_test.go", "query_test.go.tmpl", data); err != nil {
+		return fmt.Errorf("query tests: %w", err)
+	}
+	// Upload store of file and image fields
+	if err := g.renderStorage(config.TargetPath, data); err != nil {
+		return fmt.Errorf("storage: %w", err)
+	}
 	// Base handler
 	if err := g.renderGoFile(filepath.Join(config.TargetPath, "handlers"), "base.go", "base_handler.go.tmpl", data); err != nil {
 		return fmt.Errorf("base handler: %w", err)
 	}
+	if err := g.renderAuditHandler(config.TargetPath, data); err != nil {
+		return fmt.Errorf("audit handler: %w", err)
+	}
+	// API description served at /openapi.json and /docs
+	if err := g.renderOpenAPI(config.TargetPath, data); err != nil {
+		return fmt.Errorf("openapi.json: %w", err)
+	}
 
 	for _, model := range data.Models {
 		modelData := struct {
@@ -162,8 +287,8 @@ func RenderHandlers(config ProjectConfig) error {
 }
 
 // RenderHTMLTemplates generates HTML template files (templates/*.html)
-func RenderHTMLTemplates(config ProjectConfig) error {
-	g := &GormCodeGenerator{}
+func RenderHTMLTemplates(out OutputFS, config ProjectConfig) error {
+	g := &GormCodeGenerator{out: out}
 	data := buildTemplateData(config)
 
 	if err := g.renderHTMLFile(filepath.Join(config.TargetPath, "templates"), "layout.html", "layout.html.tmpl", data); err != nil {
@@ -181,22 +306,96 @@ func RenderHTMLTemplates(config ProjectConfig) error {
 			Model ModelTmplData
 		}{data, model}
 
-		listFile := strings.ToLower(model.Name) + "_list.html"
+		listFile := model.NameSnake + "_list.html"
 		if err := g.renderHTMLFile(filepath.Join(config.TargetPath, "templates"), listFile, "list.html.tmpl", modelData); err != nil {
 			return fmt.Errorf("list template %s: %w", model.Name, err)
 		}
 
-		formFile := strings.ToLower(model.Name) + "_form.html"
+		formFile := model.NameSnake + "_form.html"
 		if err := g.renderHTMLFile(filepath.Join(config.TargetPath, "templates"), formFile, "form.html.tmpl", modelData); err != nil {
 			return fmt.Errorf("form template %s: %w", model.Name, err)
 		}
+
+		importFile := model.NameSnake + "_import.html"
+		if err := g.renderHTMLFile(filepath.Join(config.TargetPath, "templates"), importFile, "import.html.tmpl", modelData); err != nil {
+			return fmt.Errorf("import template %s: %w", model.Name, err)
+		}
+	}
+	return nil
+}
+
+// RenderMigrations generates numbered up/down SQL migrations for every
+// dialect (migrations/<dbtype>/*.sql) and the embedded runner (migrations/migrate.go)
+func RenderMigrations(out OutputFS, config ProjectConfig) error {
+	if err := renderMigrationRunner(out, config); err != nil {
+		return err
+	}
+
+	dir := filepath.Join(config.TargetPath, "migrations")
+	tables := BuildSQLSchema(config)
+	for _, db := range SQLDialects {
+		m := InitialMigration(SQLDialect{DB: db}, tables)
+		if err := writeMigration(outputOrDisk(out), filepath.Join(dir, string(db)), db, m); err != nil {
+			return fmt.Errorf("%s migrations: %w", db, err)
+		}
+	}
+	return nil
+}
+
+// RenderSchemaChange generates the runner and, if the schema changed since
+// previous, migration number version altering it for every dialect
+func RenderSchemaChange(out OutputFS, previous, config ProjectConfig, version int) error {
+	if err := renderMigrationRunner(out, config); err != nil {
+		return err
+	}
+
+	old, new := BuildSQLSchema(previous), BuildSQLSchema(config)
+	if DiffSchema(old, new).Empty() {
+		return nil
+	}
+	dir := filepath.Join(config.TargetPath, "migrations")
+	for _, db := range SQLDialects {
+		m := AlterMigration(SQLDialect{DB: db}, fmt.Sprintf("%04d", version), old, new)
+		if err := writeMigration(outputOrDisk(out), filepath.Join(dir, string(db)), db, m); err != nil {
+			return fmt.Errorf("%s migrations: %w", db, err)
+		}
+	}
+	return nil
+}
+
+func renderMigrationRunner(out OutputFS, config ProjectConfig) error {
+	g := &GormCodeGenerator{out: out}
+	dir := filepath.Join(config.TargetPath, "migrations")
+	if err := g.renderGoFile(dir, "migrate.go", "migrate.go.tmpl", buildTemplateData(config)); err != nil {
+		return fmt.Errorf("migration runner: %w", err)
+	}
+	return nil
+}
+
+// writeMigration writes the up and down scripts of m into dir
+func writeMigration(out OutputFS, dir string, db DBType, m Migration) error {
+	if err := out.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, step := range []struct {
+		direction string
+		stmts     []string
+		notes     []string
+	}{{"up", m.Up, m.UpNotes}, {"down", m.Down, m.DownNotes}} {
+		header := fmt.Sprintf("%s (%s) — generated by ggami", m.FileName(step.direction), db)
+		for _, note := range step.notes {
+			header += "\n-- NOTE: " + note
+		}
+		if err := out.WriteFile(filepath.Join(dir, m.FileName(step.direction)), RenderSQL(header, step.stmts), 0644); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
 // RenderMiddleware generates RBAC middleware and auth files
-func RenderMiddleware(config ProjectConfig) error {
-	g := &GormCodeGenerator{}
+func RenderMiddleware(out OutputFS, config ProjectConfig) error {
+	g := &GormCodeGenerator{out: out}
 	data := buildTemplateData(config)
 	if config.Port > 0 {
 		data.Port = fmt.Sprintf("%d", config.Port)
@@ -207,15 +406,69 @@ func RenderMiddleware(config ProjectConfig) error {
 	if err := g.renderGoFile(filepath.Join(config.TargetPath, "models"), "user.go", "user_model.go.tmpl", data); err != nil {
 		return fmt.Errorf("user model: %w", err)
 	}
+	if err := g.renderGoFile(filepath.Join(config.TargetPath, "models"), "password_reset.go", "password_reset_model.go.tmpl", data); err != nil {
+		return fmt.Errorf("password reset model: %w", err)
+	}
+	if err := g.renderGoFile(filepath.Join(config.TargetPath, "models"), "session.go", "session_model.go.tmpl", data); err != nil {
+		return fmt.Errorf("session model: %w", err)
+	}
+	// Mailer of the password reset links
+	if err := g.renderMail(config.TargetPath, data); err != nil {
+		return fmt.Errorf("mail: %w", err)
+	}
 	if err := g.renderGoFile(filepath.Join(config.TargetPath, "middleware"), "auth.go", "middleware_auth.go.tmpl", data); err != nil {
 		return fmt.Errorf("middleware auth: %w", err)
 	}
 	if err := g.renderGoFile(filepath.Join(config.TargetPath, "middleware"), "rbac.go", "middleware_rbac.go.tmpl", data); err != nil {
 		return fmt.Errorf("middleware rbac: %w", err)
 	}
+	if err := g.renderGoFile(filepath.Join(config.TargetPath, "middleware"), "csrf.go", "middleware_csrf.go.tmpl", data); err != nil {
+		return fmt.Errorf("middleware csrf: %w", err)
+	}
+	if err := g.renderGoFile(filepath.Join(config.TargetPath, "middleware"), "ratelimit.go", "middleware_ratelimit.go.tmpl", data); err != nil {
+		return fmt.Errorf("middleware rate limit: %w", err)
+	}
+	if err := g.renderGoFile(config.TargetPath, "main_test.go", "main_test.go.tmpl", data); err != nil {
+		return fmt.Errorf("test helpers: %w", err)
+	}
+	if err := g.renderGoFile(config.TargetPath, "permissions_test.go", "permissions_test.go.tmpl", data); err != nil {
+		return fmt.Errorf("permission tests: %w", err)
+	}
+	if err := g.renderGoFile(config.TargetPath, "password_reset_test.go", "password_reset_test.go.tmpl", data); err != nil {
+		return fmt.Errorf("password reset tests: %w", err)
+	}
+	if err := g.renderGoFile(config.TargetPath, "sessions_test.go", "sessions_test.go.tmpl", data); err != nil {
+		return fmt.Errorf("session tests: %w", err)
+	}
+	if err := g.renderGoFile(config.TargetPath, "csrf_test.go", "csrf_test.go.tmpl", data); err != nil {
+		return fmt.Errorf("csrf tests: %w", err)
+	}
+	if err := g.renderGoFile(config.TargetPath, "auth_test.go", "auth_test.go.tmpl", data); err != nil {
+		return fmt.Errorf("auth tests: %w", err)
+	}
+	if data.HasOwners || data.HasTenants {
+		if err := g.renderGoFile(config.TargetPath, "scoping_test.go", "scoping_test.go.tmpl", data); err != nil {
+			return fmt.Errorf("scoping tests: %w", err)
+		}
+	}
 	if err := g.renderGoFile(filepath.Join(config.TargetPath, "handlers"), "auth.go", "auth_handler.go.tmpl", data); err != nil {
 		return fmt.Errorf("auth handler: %w", err)
 	}
+	if err := g.renderGoFile(filepath.Join(config.TargetPath, "handlers"), "sessions.go", "session_handler.go.tmpl", data); err != nil {
+		return fmt.Errorf("session handler: %w", err)
+	}
+	if err := g.renderGoFile(filepath.Join(config.TargetPath, "handlers"), "login_throttle.go", "login_throttle.go.tmpl", data); err != nil {
+		return fmt.Errorf("login throttle: %w", err)
+	}
+	if err := g.renderGoFile(filepath.Join(config.TargetPath, "handlers"), "users.go", "users_handler.go.tmpl", data); err != nil {
+		return fmt.Errorf("users handler: %w", err)
+	}
+	if err := g.renderHTMLFile(filepath.Join(config.TargetPath, "templates"), "users.html", "users.html.tmpl", data); err != nil {
+		return fmt.Errorf("users template: %w", err)
+	}
+	if err := g.renderTwoFactor(config.TargetPath, data); err != nil {
+		return fmt.Errorf("two-factor authentication: %w", err)
+	}
 	if err := g.renderHTMLFile(filepath.Join(config.TargetPath, "templates"), "login.html", "login.html.tmpl", data); err != nil {
 		return fmt.Errorf("login template: %w", err)
 	}
@@ -225,6 +478,9 @@ func RenderMiddleware(config ProjectConfig) error {
 	if err := g.renderHTMLFile(filepath.Join(config.TargetPath, "templates"), "forgot_password.html", "forgot_password.html.tmpl", data); err != nil {
 		return fmt.Errorf("forgot_password template: %w", err)
 	}
+	if err := g.renderHTMLFile(filepath.Join(config.TargetPath, "templates"), "reset_password.html", "reset_password.html.tmpl", data); err != nil {
+		return fmt.Errorf("reset_password template: %w", err)
+	}
 	return nil
 }
 
@@ -245,7 +501,91 @@ func (g *GormCodeGenerator) renderGoFile(dir, filename, tmplName string, data in
 		return fmt.Errorf("execute template %s: %w", tmplName, err)
 	}
 
-	return os.WriteFile(filepath.Join(dir, filename), []byte(buf.String()), 0644)
+	return outputOrDisk(g.out).WriteFile(filepath.Join(dir, filename), []byte(buf.String()), 0644)
+}
+
+// renderStorage writes the storage package when a model has file fields
+func (g *GormCodeGenerator) renderStorage(targetPath string, data TemplateData) error {
+	if !data.HasUploads {
+		return nil
+	}
+	files := [][2]string{
+		{"storage.go", "storage.go.tmpl"},
+		{"local.go", "storage_local.go.tmpl"},
+		{"s3.go", "storage_s3.go.tmpl"},
+		{"storage_test.go", "storage_test.go.tmpl"},
+	}
+	if data.HasImages {
+		files = append(files, [2]string{"image.go", "storage_image.go.tmpl"})
+	}
+	for _, f := range files {
+		if err := g.renderGoFile(filepath.Join(targetPath, "storage"), f[0], f[1], data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderMail writes the mail package, which sends the password reset links
+// of projects with RBAC
+func (g *GormCodeGenerator) renderMail(targetPath string, data TemplateData) error {
+	files := [][2]string{
+		{"mail.go", "mail.go.tmpl"},
+		{"smtp.go", "mail_smtp.go.tmpl"},
+		{"local.go", "mail_local.go.tmpl"},
+		{"mail_test.go", "mail_test.go.tmpl"},
+	}
+	for _, f := range files {
+		if err := g.renderGoFile(filepath.Join(targetPath, "mail"), f[0], f[1], data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderTwoFactor writes the models, middleware, handlers, pages and tests
+// of TOTP two-factor authentication when RBAC has it
+func (g *GormCodeGenerator) renderTwoFactor(targetPath string, data TemplateData) error {
+	if !data.HasTwoFactor {
+		return nil
+	}
+	files := [][3]string{
+		{"models", "recovery_code.go", "recovery_code_model.go.tmpl"},
+		{"models", "two_factor_role.go", "two_factor_role_model.go.tmpl"},
+		{"middleware", "twofactor.go", "middleware_twofactor.go.tmpl"},
+		{"handlers", "totp.go", "totp.go.tmpl"},
+		{"handlers", "two_factor.go", "two_factor_handler.go.tmpl"},
+		{"", "two_factor_test.go", "two_factor_test.go.tmpl"},
+	}
+	for _, f := range files {
+		if err := g.renderGoFile(filepath.Join(targetPath, f[0]), f[1], f[2], data); err != nil {
+			return err
+		}
+	}
+	for _, page := range []string{"two_factor.html", "two_factor_setup.html"} {
+		if err := g.renderHTMLFile(filepath.Join(targetPath, "templates"), page, page+".tmpl", data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderAuditHandler writes the audit log helpers and dashboard page when a
+// model is audited
+func (g *GormCodeGenerator) renderAuditHandler(targetPath string, data TemplateData) error {
+	if !data.HasAudit {
+		return nil
+	}
+	return g.renderGoFile(filepath.Join(targetPath, "handlers"), "audit.go", "audit_handler.go.tmpl", data)
+}
+
+// renderOpenAPI writes openapi.json, which main.go embeds
+func (g *GormCodeGenerator) renderOpenAPI(targetPath string, data TemplateData) error {
+	spec, err := BuildOpenAPI(data)
+	if err != nil {
+		return err
+	}
+	return outputOrDisk(g.out).WriteFile(filepath.Join(targetPath, "openapi.json"), spec, 0644)
 }
 
 // renderBasePages generates all dashboard base page templates and the base handler
@@ -254,6 +594,9 @@ func (g *GormCodeGenerator) renderBasePages(targetPath string, data TemplateData
 	if err := g.renderGoFile(filepath.Join(targetPath, "handlers"), "base.go", "base_handler.go.tmpl", data); err != nil {
 		return fmt.Errorf("base handler: %w", err)
 	}
+	if err := g.renderAuditHandler(targetPath, data); err != nil {
+		return fmt.Errorf("audit handler: %w", err)
+	}
 
 	// Base HTML pages (use << >> delimiters)
 	basePages := []struct {
@@ -273,6 +616,12 @@ func (g *GormCodeGenerator) renderBasePages(targetPath string, data TemplateData
 		{"blank.html", "blank.html.tmpl"},
 		{"404.html", "404.html.tmpl"},
 	}
+	if data.HasAudit {
+		basePages = append(basePages, struct {
+			output string
+			tmpl   string
+		}{"audit_log.html", "audit_log.html.tmpl"})
+	}
 
 	tmplDir := filepath.Join(targetPath, "templates")
 	for _, p := range basePages {
@@ -302,5 +651,5 @@ func (g *GormCodeGenerator) renderHTMLFile(dir, filename, tmplName string, data
 		return fmt.Errorf("execute template %s: %w", tmplName, err)
 	}
 
-	return os.WriteFile(filepath.Join(dir, filename), []byte(buf.String()), 0644)
+	return outputOrDisk(g.out).WriteFile(filepath.Join(dir, filename), []byte(buf.String()), 0644)
 }
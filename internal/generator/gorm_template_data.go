@@ -1,6 +1,10 @@
 package generator
 
 import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 	"unicode"
@@ -19,7 +23,43 @@ type TemplateData struct {
 	Driver      DBDriverInfo
 	RBAC        *RBACConfig
 	HasRBAC     bool
-	RBACMatrix  string // Pre-built Go source for permission matrix
+	RBACMatrix  string   // Pre-built Go source for permission matrix
+	ExtraDeps   []string // go.mod requirements added by field types
+	HasUploads  bool     // some model has a file field
+	HasImages   bool     // some model has an image field, whose uploads get thumbnails
+	HasAudit    bool     // some model writes to audit_logs
+	HasOwners   bool     // some model has an OwnerField
+	HasTenants  bool     // some model has a TenantField; users and audit_logs get a tenant ID
+	AuditModels []string // names of the models with Audit
+	OwnedAuditModels []string // names of the models with Audit and an OwnerField
+	AutoMigrate bool     // run gorm AutoMigrate at startup
+	Dialect     string   // migrations/<Dialect> scripts embedded in the binary
+
+	Storage StorageTmplData // upload store, used if HasUploads
+	Mail    MailTmplData    // mailer of the password reset, used if HasRBAC
+
+	DefaultRole string          // role of users who register, used if HasRBAC
+	RateLimit   RateLimitConfig // requests per minute per client IP with defaults applied, 0 for no limit
+	HasTwoFactor bool           // users can set up TOTP two-factor authentication, used if HasRBAC
+}
+
+// StorageTmplData is the upload store configuration with defaults applied
+type StorageTmplData struct {
+	Driver    string
+	Root      string
+	MaxSizeMB int
+	Types     []string
+	S3        S3Config
+}
+
+// MailTmplData is the mail configuration with defaults applied
+type MailTmplData struct {
+	Driver   string
+	From     string
+	BaseURL  string // without a trailing slash
+	Dir      string
+	SMTPHost string
+	SMTPPort int
 }
 
 // ModelTmplData is per-model data for templates
@@ -28,18 +68,77 @@ type ModelTmplData struct {
 	NameLower  string // camelCase first char lower
 	NameSnake  string // snake_case
 	NamePlural string // simple plural
+	TableName  string // explicit table name, "" for GORM's default
 	Fields     []FieldTmplData
+	Relations  []RelationTmplData
+
+	ModelStdImports     []string        // standard library imports needed by field Go types
+	ModelThirdImports   []string        // third-party imports needed by field Go types
+	HandlerStdImports   []string        // extra standard library imports for the handler
+	HandlerThirdImports []string        // extra third-party imports for the handler
+	HasFile             bool            // form must be multipart
+	FileFields          []FieldTmplData // file and image fields, served by Download
+	HasImage            bool            // some field is an image with a thumbnail
+	Timestamps          bool            // Fields end with CreatedAt and UpdatedAt
+	SoftDelete          bool            // has DeletedAt; deleted records go to the trash
+	Audit               bool            // changes are written to audit_logs
+	OwnerField          string          // field holding the creator's user ID, "" if records have no owner
+	OwnerColumn         string          // column of OwnerField
+	TenantField         string          // field holding the tenant ID, "" if records are not per tenant
+	TenantColumn        string          // column of TenantField
+	Scoped              bool            // OwnerField or TenantField: queries go through <NameLower>Scope
+	HasBelongsTo        bool            // handlers check the parent records exist before saving
+	HasMany2Many        bool            // create and update set the associations in their transaction
+	HasUnique           bool            // some field has a unique rule, checked across the rows of an import
 }
 
 // FieldTmplData is per-field data for templates
 type FieldTmplData struct {
-	Name       string
-	Type       string // Go type
-	GormTag    string // full GORM tag string
-	JsonName   string
-	InputType  string // HTML input type
-	DefaultVal string
-	IsID       bool
+	Name         string
+	Type         string // Go type
+	GormTag      string // full GORM tag string
+	JsonName     string
+	InputType    string // HTML input type
+	DefaultVal   string
+	IsID         bool
+	IsForeignKey bool // key of a belongsTo relation, edited through a select
+	Nullable     bool // key of an optional belongsTo: a pointer, NULL when empty
+	IsAuto       bool // set by GORM or the handlers (timestamps, owner, tenant), never read from requests
+
+	Kind       string   // field type registry name: "decimal", "enum", ...
+	Step       string   // number input step
+	Searchable bool     // included in the list page search
+	Filter     string   // query.Kind constant for list filters and sorting, "" if excluded
+	EnumValues []string // allowed values for enum fields
+	ParseCode  string   // handler code reading the field from the form
+	FormValue  string   // edit form value expression
+	Text       string   // export expression formatting item's field as text
+	Cell       string   // list cell expression
+
+	ValidateCode string // handler checks for the field's validation rules
+	Attrs        string // matching HTML5 input attributes
+	PatternVar   string // package-level regexp variable, if the field has a pattern
+	PatternExpr  string // quoted Go regex for PatternVar
+	Rules        *FieldValidation // validation rules, documented in openapi.json
+}
+
+// RelationTmplData is per-relation data for templates
+type RelationTmplData struct {
+	Name         string // association field: "Customer", "Tags"
+	Type         RelationType
+	Model        string // target model: "Customer"
+	ModelSnake   string // target snake_case, used for routes: "customer"
+	ForeignKey   string // belongsTo: key on this model; hasMany: key on the target
+	JoinTable    string // many2many join table
+	JsonName     string
+	FormName     string // form input name: "customer_id", "tags_ids"
+	DisplayField string // target field shown in selects and list cells
+	TargetIDType string // Go type of the target's ID
+	IsBelongsTo  bool
+	IsHasMany    bool
+	IsMany2Many  bool
+	TargetScoped bool // the target has an owner or tenant scope
+	Optional     bool // belongsTo: the key may be empty, stored as NULL
 }
 
 // GormFuncMap provides template helper functions
@@ -52,6 +151,7 @@ var GormFuncMap = template.FuncMap{
 	"inputType": htmlInputType,
 	"join":    strings.Join,
 	"joinGormTags": joinGormTags,
+	"indent":  indent,
 }
 
 func buildTemplateData(config ProjectConfig) TemplateData {
@@ -66,37 +166,105 @@ func buildTemplateData(config ProjectConfig) TemplateData {
 	}
 
 	var models []ModelTmplData
+	var extraDeps []string
+	hasUploads, hasImages := false, false
+	var auditModels []string
 	for _, m := range config.Models {
 		mtd := ModelTmplData{
 			Name:       m.Name,
 			NameLower:  lowerFirst(m.Name),
 			NameSnake:  toSnakeCase(m.Name),
 			NamePlural: simplePlural(m.Name),
+			TableName:  m.TableName,
+			Timestamps: m.Timestamps,
+			SoftDelete: m.SoftDelete,
+			Audit:      m.Audit,
 		}
+		var modelImports, handlerImports []string
 		for _, f := range m.Fields {
-			jsonName := f.JsonName
-			if jsonName == "" {
-				jsonName = toSnakeCase(f.Name)
+			ft := lookupFieldTypeOrString(f.Type)
+			ftd := newFieldTmplData(m.Name, f)
+			mtd.Fields = append(mtd.Fields, ftd)
+			mtd.HasUnique = mtd.HasUnique || (ftd.Rules != nil && ftd.Rules.Unique)
+
+			modelImports = append(modelImports, ft.Imports...)
+			if !ftd.IsID {
+				handlerImports = append(handlerImports, ft.Imports...)
+				handlerImports = append(handlerImports, ft.ParseDeps...)
+				handlerImports = append(handlerImports, validationImports(f.Validation)...)
 			}
-			ftd := FieldTmplData{
-				Name:       f.Name,
-				Type:       goType(f.Type),
-				GormTag:    joinGormTags(f.GormTags),
-				JsonName:   jsonName,
-				InputType:  htmlInputType(f.Type),
-				DefaultVal: f.DefaultVal,
-				IsID:       containsTag(f.GormTags, "primaryKey"),
+			extraDeps = append(extraDeps, ft.GoModDeps...)
+			if ft.InputType == "file" {
+				mtd.HasFile = true
+				mtd.FileFields = append(mtd.FileFields, ftd)
+				hasUploads = true
+				mtd.HasImage = mtd.HasImage || ft.Name == "image"
+				hasImages = hasImages || mtd.HasImage
 			}
-			mtd.Fields = append(mtd.Fields, ftd)
 		}
+		if m.Timestamps {
+			modelImports = append(modelImports, "time")
+		}
+		if m.SoftDelete {
+			modelImports = append(modelImports, "gorm.io/gorm")
+		}
+		if m.Audit {
+			auditModels = append(auditModels, m.Name)
+		}
+		mtd.ModelStdImports, mtd.ModelThirdImports = splitImports(modelImports)
+		mtd.HandlerStdImports, mtd.HandlerThirdImports = splitImports(handlerImports)
 		models = append(models, mtd)
 	}
+	resolveRelations(config.Models, models)
+	hasOwners, hasTenants := false, false
+	for i := range models {
+		if models[i].Timestamps {
+			addTimestamps(&models[i])
+		}
+		if f := config.Models[i].OwnerField; f != "" {
+			models[i].OwnerField, models[i].OwnerColumn = f, addScopeField(&models[i], f)
+			hasOwners = true
+		}
+		if f := config.Models[i].TenantField; f != "" {
+			models[i].TenantField, models[i].TenantColumn = f, addScopeField(&models[i], f)
+			hasTenants = true
+		}
+		models[i].Scoped = models[i].OwnerField != "" || models[i].TenantField != ""
+		models[i].HandlerStdImports, _ = splitImports(append(models[i].HandlerStdImports, codeImports(models[i])...))
+	}
+	scoped := make(map[string]bool)
+	var ownedAuditModels []string
+	for _, m := range models {
+		scoped[m.Name] = m.Scoped
+		if m.Audit && m.OwnerField != "" {
+			ownedAuditModels = append(ownedAuditModels, m.Name)
+		}
+	}
+	for i := range models {
+		for j := range models[i].Relations {
+			models[i].Relations[j].TargetScoped = scoped[models[i].Relations[j].Model]
+			models[i].HasBelongsTo = models[i].HasBelongsTo || models[i].Relations[j].IsBelongsTo
+			models[i].HasMany2Many = models[i].HasMany2Many || models[i].Relations[j].IsMany2Many
+		}
+	}
 
 	hasRBAC := config.RBAC != nil && config.RBAC.Enabled
 
 	rbacMatrix := ""
+	defaultRole := ""
+	var rateLimit RateLimitConfig
 	if hasRBAC {
 		rbacMatrix = buildRBACMatrixSource(config.RBAC)
+		defaultRole = config.RBAC.DefaultRole
+		if defaultRole == "" && len(config.RBAC.Roles) > 0 {
+			defaultRole = config.RBAC.Roles[len(config.RBAC.Roles)-1]
+		}
+		rateLimit = buildRateLimitData(config.RBAC.RateLimit)
+	}
+
+	dialect := config.DBType
+	if dialect == "" {
+		dialect = DBTypeSQLite
 	}
 
 	return TemplateData{
@@ -112,14 +280,381 @@ func buildTemplateData(config ProjectConfig) TemplateData {
 		RBAC:        config.RBAC,
 		HasRBAC:     hasRBAC,
 		RBACMatrix:  rbacMatrix,
+		ExtraDeps:   dedupSorted(extraDeps),
+		HasUploads:  hasUploads,
+		HasImages:   hasImages,
+		HasAudit:    len(auditModels) > 0,
+		HasOwners:   hasOwners,
+		HasTenants:  hasTenants,
+		AuditModels: auditModels,
+		OwnedAuditModels: ownedAuditModels,
+		Storage:     buildStorageData(config.Storage),
+		Mail:        buildMailData(config.Mail, config.Port),
+		AutoMigrate: config.AutoMigrate,
+		Dialect:     string(dialect),
+		DefaultRole: defaultRole,
+		RateLimit:   rateLimit,
+		HasTwoFactor: hasRBAC && config.RBAC.TwoFactor,
+	}
+}
+
+// buildRateLimitData applies the defaults to the rate limits: 30 requests per
+// minute to the auth routes, none to the others. -1 becomes 0, no limit.
+func buildRateLimitData(rl *RateLimitConfig) RateLimitConfig {
+	limits := RateLimitConfig{Auth: 30}
+	if rl == nil {
+		return limits
+	}
+	if rl.Auth != 0 {
+		limits.Auth = rl.Auth
+	}
+	limits.Dashboard, limits.Models = rl.Dashboard, rl.Models
+	for _, limit := range []*int{&limits.Auth, &limits.Dashboard, &limits.Models} {
+		*limit = max(*limit, 0)
+	}
+	return limits
+}
+
+// buildStorageData applies the defaults to the storage settings: local
+// files under uploads/, at most 10 MB each
+func buildStorageData(s *StorageConfig) StorageTmplData {
+	st := StorageTmplData{Driver: "local", Root: "uploads", MaxSizeMB: 10}
+	if s == nil {
+		return st
+	}
+	if s.Driver != "" {
+		st.Driver = s.Driver
+	}
+	if s.Root != "" {
+		st.Root = s.Root
+	}
+	if s.MaxSizeMB > 0 {
+		st.MaxSizeMB = s.MaxSizeMB
+	}
+	st.Types = s.AllowedTypes
+	if s.S3 != nil {
+		st.S3 = *s.S3
+	}
+	return st
+}
+
+// buildMailData applies the defaults to the mail settings: mails printed to
+// the console, sent from no-reply@localhost, with links to the local server
+func buildMailData(m *MailConfig, port int) MailTmplData {
+	if port <= 0 {
+		port = 8080
+	}
+	md := MailTmplData{
+		Driver:   "console",
+		From:     "no-reply@localhost",
+		BaseURL:  "http://localhost:" + strconv.Itoa(port),
+		Dir:      "mail",
+		SMTPPort: 587,
+	}
+	if m == nil {
+		return md
+	}
+	if m.Driver != "" {
+		md.Driver = m.Driver
+	}
+	if m.From != "" {
+		md.From = m.From
+	}
+	if m.BaseURL != "" {
+		md.BaseURL = strings.TrimRight(m.BaseURL, "/")
+	}
+	if m.Dir != "" {
+		md.Dir = m.Dir
+	}
+	if m.SMTP != nil {
+		md.SMTPHost = m.SMTP.Host
+		if m.SMTP.Port > 0 {
+			md.SMTPPort = m.SMTP.Port
+		}
+	}
+	return md
+}
+
+// codeImports lists fmt, strconv and strings for a handler whose field
+// parsing, validation, export or many2many code uses them
+func codeImports(m ModelTmplData) []string {
+	var code strings.Builder
+	for _, f := range m.Fields {
+		if !f.IsID && !f.IsAuto {
+			code.WriteString(f.ParseCode)
+			code.WriteString(f.ValidateCode)
+		}
+		code.WriteString(f.Text)
+	}
+	var imports []string
+	for _, r := range m.Relations {
+		if r.IsMany2Many {
+			imports = append(imports, "strconv")
+		}
+	}
+	for _, pkg := range []string{"fmt", "strconv", "strings"} {
+		if strings.Contains(code.String(), pkg+".") {
+			imports = append(imports, pkg)
+		}
+	}
+	return imports
+}
+
+// newFieldTmplData resolves a field definition through the field type registry
+func newFieldTmplData(model string, f FieldDef) FieldTmplData {
+	jsonName := f.JsonName
+	if jsonName == "" {
+		jsonName = toSnakeCase(f.Name)
+	}
+	ft := lookupFieldTypeOrString(f.Type)
+	if ft.Text == "" {
+		ft.Text = `fmt.Sprint(item.{{.Field}})`
+	}
+	snippet := FieldTypeSnippetData{Field: f.Name, Form: jsonName, Values: f.EnumValues, Model: toSnakeCase(model)}
+	ftd := FieldTmplData{
+		Name:       f.Name,
+		Type:       ft.GoType,
+		GormTag:    mergeGormTags(ft.GormTag, f.GormTags),
+		JsonName:   jsonName,
+		InputType:  ft.InputType,
+		DefaultVal: f.DefaultVal,
+		IsID:       containsTag(f.GormTags, "primaryKey"),
+		Kind:       ft.Name,
+		Step:       ft.Step,
+		Searchable: ft.Searchable,
+		Filter:     ft.Filter,
+		EnumValues: f.EnumValues,
+		ParseCode:  indent(renderSnippet(ft.Parse, snippet), "\t"),
+		FormValue:  renderSnippet(ft.FormValue, snippet),
+		Text:       renderSnippet(ft.Text, snippet),
+		Cell:       renderSnippet(ft.Cell, snippet),
+	}
+
+	if v := f.Validation; v != nil && !ftd.IsID {
+		if v.Email && ftd.InputType == "text" {
+			ftd.InputType = "email"
+		}
+		ftd.Rules = v
+		ftd.ValidateCode = indent(validationCode(model, ftd, v), "\t")
+		ftd.Attrs = validationAttrs(ftd, v)
+		if v.Pattern != "" {
+			ftd.PatternVar = patternVar(model, f.Name)
+			ftd.PatternExpr = strconv.Quote(anchoredPattern(v.Pattern))
+		}
 	}
+	return ftd
+}
+
+// addTimestamps appends the CreatedAt and UpdatedAt fields GORM sets on
+// create and save
+func addTimestamps(m *ModelTmplData) {
+	for _, name := range []string{"CreatedAt", "UpdatedAt"} {
+		f := newFieldTmplData(m.Name, FieldDef{Name: name, Type: "time.Time"})
+		f.IsAuto = true
+		m.Fields = append(m.Fields, f)
+	}
+}
+
+// addScopeField marks the owner or tenant field name of m as set by the
+// handlers, adding it as an indexed uint if the model does not declare it.
+// Returns the field's column.
+func addScopeField(m *ModelTmplData, name string) string {
+	column := gormNaming.ColumnName("", name)
+	for i := range m.Fields {
+		if m.Fields[i].Name == name {
+			m.Fields[i].IsAuto = true
+			return column
+		}
+	}
+	f := newFieldTmplData(m.Name, FieldDef{Name: name, Type: "uint", JsonName: column, GormTags: []string{"index"}})
+	f.IsAuto = true
+	m.Fields = append(m.Fields, f)
+	return column
+}
+
+// resolveRelations fills ModelTmplData.Relations and adds missing foreign key
+// fields. A hasMany relation also gives its target an implicit belongsTo back
+// to the owner (unless one exists), so the child form gets a parent select.
+func resolveRelations(defs []ModelDef, models []ModelTmplData) {
+	index := make(map[string]int)
+	for i, m := range models {
+		index[m.Name] = i
+	}
+
+	// implicit belongsTo relations are added last, so explicit ones declared
+	// by models later in the list take precedence
+	type backRef struct{ child, parent, fk string }
+	var implicit []backRef
+
+	for _, def := range defs {
+		owner := &models[index[def.Name]]
+		for _, rel := range def.Relations {
+			ti, ok := index[rel.Model]
+			if !ok {
+				continue // rejected by validation
+			}
+			target := &models[ti]
+
+			rtd := RelationTmplData{
+				Name:         rel.Name,
+				Type:         rel.Type,
+				Model:        target.Name,
+				ModelSnake:   target.NameSnake,
+				JsonName:     toSnakeCase(rel.Name),
+				DisplayField: displayField(rel, *target),
+				TargetIDType: idType(*target),
+			}
+
+			switch rel.Type {
+			case RelationBelongsTo:
+				rtd.IsBelongsTo = true
+				rtd.ForeignKey = rel.ResolvedForeignKey(def.Name)
+				rtd.FormName = ensureForeignKey(owner, rtd.ForeignKey, rel.Optional)
+			case RelationHasMany:
+				rtd.IsHasMany = true
+				rtd.ForeignKey = rel.ResolvedForeignKey(def.Name)
+				ensureForeignKey(target, rtd.ForeignKey, rel.Optional)
+				implicit = append(implicit, backRef{target.Name, owner.Name, rtd.ForeignKey})
+			case RelationMany2Many:
+				rtd.IsMany2Many = true
+				rtd.JoinTable = rel.JoinTable
+				if rtd.JoinTable == "" {
+					rtd.JoinTable = owner.NameSnake + "_" + toSnakeCase(rel.Name)
+				}
+				rtd.FormName = toSnakeCase(rel.Name) + "_ids"
+			}
+			owner.Relations = append(owner.Relations, rtd)
+		}
+	}
+	for _, b := range implicit {
+		addImplicitBelongsTo(&models[index[b.child]], &models[index[b.parent]], b.fk)
+	}
+	// a key made optional by any relation is optional for all of them
+	for i := range models {
+		for j, r := range models[i].Relations {
+			if r.IsBelongsTo {
+				models[i].Relations[j].Optional = nullableField(models[i], r.ForeignKey)
+			}
+		}
+	}
+}
+
+// ensureForeignKey marks field fk of m as a foreign key, adding it (NOT NULL)
+// if the model does not declare it. The key of an optional relation becomes
+// a nullable pointer. Returns the field's JSON/form name.
+func ensureForeignKey(m *ModelTmplData, fk string, optional bool) string {
+	i := slices.IndexFunc(m.Fields, func(f FieldTmplData) bool { return f.Name == fk })
+	if i < 0 {
+		f := newFieldTmplData(m.Name, FieldDef{Name: fk, Type: "uint", JsonName: gormNaming.ColumnName("", fk), GormTags: []string{"not null", "index"}})
+		m.Fields = append(m.Fields, f)
+		i = len(m.Fields) - 1
+	}
+	f := &m.Fields[i]
+	f.IsForeignKey = true
+	if optional && !f.Nullable {
+		nullableKey(f)
+	}
+	return f.JsonName
+}
+
+// nullableKey turns an int or uint key into a pointer: an empty value clears
+// it, and the column drops NOT NULL
+func nullableKey(f *FieldTmplData) {
+	parse := "strconv.ParseUint(s, 10, 64)"
+	if f.Kind == "int" {
+		parse = "strconv.ParseInt(s, 10, 64)"
+	}
+	var tags []string
+	for _, tag := range strings.Split(f.GormTag, ";") {
+		if !strings.EqualFold(strings.TrimSpace(tag), "not null") {
+			tags = append(tags, tag)
+		}
+	}
+	f.GormTag = strings.Join(tags, ";")
+	f.ParseCode = indent(fmt.Sprintf(`if s := r.FormValue(%[1]q); s == "" {
+	item.%[2]s = nil
+} else if v, err := %[3]s; err == nil {
+	key := %[4]s(v)
+	item.%[2]s = &key
+} else {
+	errs.add(%[1]q, "0 이상의 정수를 입력하세요")
+}`, f.JsonName, f.Name, parse, f.Type), "\t")
+	f.Type = "*" + f.Type
+	f.Nullable = true
+	f.Text = "keyText(item." + f.Name + ")"
+	f.FormValue = `{{with .Item.` + f.Name + `}}{{.}}{{end}}`
+	f.Cell = `{{with .` + f.Name + `}}{{.}}{{end}}`
+}
+
+// nullableField reports whether field name of m is a nullable key
+func nullableField(m ModelTmplData, name string) bool {
+	for _, f := range m.Fields {
+		if f.Name == name {
+			return f.Nullable
+		}
+	}
+	return false
+}
+
+func addImplicitBelongsTo(child, parent *ModelTmplData, fk string) {
+	for _, r := range child.Relations {
+		if r.IsBelongsTo && r.Model == parent.Name && r.ForeignKey == fk {
+			return
+		}
+		if r.Name == parent.Name {
+			return // name taken, keep the plain key field
+		}
+	}
+	formName := gormNaming.ColumnName("", fk)
+	for _, f := range child.Fields {
+		if f.Name == parent.Name {
+			return
+		}
+		if f.Name == fk {
+			formName = f.JsonName
+		}
+	}
+	child.Relations = append(child.Relations, RelationTmplData{
+		Name:         parent.Name,
+		Type:         RelationBelongsTo,
+		Model:        parent.Name,
+		ModelSnake:   parent.NameSnake,
+		ForeignKey:   fk,
+		JsonName:     parent.NameSnake,
+		FormName:     formName,
+		DisplayField: displayField(RelationDef{}, *parent),
+		TargetIDType: idType(*parent),
+		IsBelongsTo:  true,
+	})
+}
+
+// displayField picks the target field shown for a related record
+func displayField(rel RelationDef, target ModelTmplData) string {
+	if rel.DisplayField != "" {
+		return rel.DisplayField
+	}
+	for _, f := range target.Fields {
+		if f.Kind == "string" && !f.IsID {
+			return f.Name
+		}
+	}
+	return "ID"
+}
+
+func idType(m ModelTmplData) string {
+	for _, f := range m.Fields {
+		if f.Name == "ID" {
+			return f.Type
+		}
+	}
+	return "uint"
 }
 
 func buildRBACMatrixSource(rbac *RBACConfig) string {
 	var b strings.Builder
 	b.WriteString("map[string]map[string]Permission{\n")
 	for _, role := range rbac.Roles {
-		b.WriteString("\t\"" + role + "\": {\n")
+		b.WriteString("\t" + strconv.Quote(role) + ": {\n")
 		for _, mp := range rbac.ModelPerms {
 			for _, perm := range mp.Permissions {
 				if perm.Role == role {
@@ -185,39 +720,34 @@ func simplePlural(s string) string {
 }
 
 func goType(t string) string {
-	switch t {
-	case "string":
-		return "string"
-	case "int":
-		return "int"
-	case "uint":
-		return "uint"
-	case "float64":
-		return "float64"
-	case "bool":
-		return "bool"
-	case "time.Time":
-		return "time.Time"
-	default:
-		return "string"
-	}
+	return lookupFieldTypeOrString(t).GoType
 }
 
 func htmlInputType(t string) string {
-	switch t {
-	case "string":
-		return "text"
-	case "int", "uint":
-		return "number"
-	case "float64":
-		return "number"
-	case "bool":
-		return "checkbox"
-	case "time.Time":
-		return "datetime-local"
-	default:
-		return "text"
+	return lookupFieldTypeOrString(t).InputType
+}
+
+// lookupFieldTypeOrString falls back to string for unknown types,
+// which validation rejects before generation
+func lookupFieldTypeOrString(t string) FieldType {
+	if ft, ok := LookupFieldType(t); ok {
+		return ft
+	}
+	ft, _ := LookupFieldType("string")
+	return ft
+}
+
+func dedupSorted(items []string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, it := range items {
+		if !seen[it] {
+			seen[it] = true
+			out = append(out, it)
+		}
 	}
+	sort.Strings(out)
+	return out
 }
 
 func joinGormTags(tags []string) string {
@@ -0,0 +1,118 @@
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"ggami-go/internal/domain"
+)
+
+func TestForeignKeyNames(t *testing.T) {
+	config := ProjectConfig{
+		ProjectName: "shop",
+		TargetPath:  "out",
+		GormMode:    true,
+		DBType:      domain.DBTypeSQLite,
+		Models: []ModelDef{
+			{Name: "Customer", Fields: []FieldDef{{Name: "Name", Type: "string"}},
+				Relations: []RelationDef{{Name: "Invoices", Type: RelationHasMany, Model: "Invoice"}}},
+			{Name: "Order", Fields: []FieldDef{{Name: "Total", Type: "int"}},
+				Relations: []RelationDef{{Name: "Customer", Type: RelationBelongsTo, Model: "Customer"}}},
+			{Name: "Invoice", Fields: []FieldDef{{Name: "Total", Type: "int"}}},
+		},
+	}
+
+	data := buildTemplateData(config)
+	for _, m := range data.Models {
+		for _, r := range m.Relations {
+			if r.IsBelongsTo && r.FormName != "customer_id" {
+				t.Errorf("%s.%s: form name %q, want customer_id", m.Name, r.Name, r.FormName)
+			}
+		}
+	}
+
+	out := NewMemFS()
+	if err := out.MkdirAll(filepath.Join("out", "models"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := RenderModels(out, config); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{"order.go", "invoice.go"} {
+		src, err := out.ReadFile(filepath.Join("out", "models", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(src), `json:"customer_id"`) {
+			t.Errorf("%s: no json:\"customer_id\" tag in\n%s", file, src)
+		}
+	}
+}
+
+func TestForeignKeysNotNull(t *testing.T) {
+	config := ProjectConfig{
+		ProjectName: "shop",
+		GormMode:    true,
+		Models: []ModelDef{
+			{Name: "Customer", Fields: []FieldDef{{Name: "ID", Type: "uint", GormTags: []string{"primaryKey"}}}},
+			{Name: "Order", Fields: []FieldDef{{Name: "ID", Type: "uint", GormTags: []string{"primaryKey"}}},
+				Relations: []RelationDef{{Name: "Customer", Type: RelationBelongsTo, Model: "Customer"}}},
+		},
+	}
+	for _, table := range BuildSQLSchema(config) {
+		for _, col := range table.Columns {
+			if col.Name == "customer_id" && !col.NotNull {
+				t.Errorf("%s.customer_id is nullable, but handlers and the form require it", table.Name)
+			}
+		}
+	}
+}
+
+func TestOptionalForeignKeys(t *testing.T) {
+	id := FieldDef{Name: "ID", Type: "uint", GormTags: []string{"primaryKey"}}
+	config := ProjectConfig{
+		ProjectName: "shop",
+		GormMode:    true,
+		Models: []ModelDef{
+			{Name: "Team", Fields: []FieldDef{id},
+				Relations: []RelationDef{{Name: "Captain", Type: RelationBelongsTo, Model: "Player", Optional: true}}},
+			{Name: "Player", Fields: []FieldDef{id},
+				Relations: []RelationDef{{Name: "Team", Type: RelationBelongsTo, Model: "Team"}}},
+		},
+	}
+
+	for _, m := range buildTemplateData(config).Models {
+		for _, f := range m.Fields {
+			if f.Name == "CaptainID" && (f.Type != "*uint" || !f.Nullable) {
+				t.Errorf("Team.CaptainID: type %s, nullable %v; want *uint, true", f.Type, f.Nullable)
+			}
+			if f.Name == "TeamID" && (f.Type != "uint" || f.Nullable) {
+				t.Errorf("Player.TeamID: type %s, nullable %v; want uint, false", f.Type, f.Nullable)
+			}
+		}
+		for _, r := range m.Relations {
+			if r.Optional != (r.Name == "Captain") {
+				t.Errorf("%s.%s: optional = %v", m.Name, r.Name, r.Optional)
+			}
+		}
+	}
+
+	tables := BuildSQLSchema(config)
+	for _, table := range tables {
+		for _, col := range table.Columns {
+			if col.Name == "captain_id" && col.NotNull {
+				t.Errorf("%s.captain_id is NOT NULL, but the relation is optional", table.Name)
+			}
+		}
+	}
+
+	// the cycle leaves one key to add after both tables exist
+	for _, db := range SQLDialects {
+		up := strings.Join(InitialMigration(SQLDialect{DB: db}, tables).Up, "\n")
+		altered := strings.Contains(up, "ALTER TABLE")
+		if want := db != DBTypeSQLite; altered != want {
+			t.Errorf("%s: ALTER TABLE in the initial migration = %v, want %v\n%s", db, altered, want, up)
+		}
+	}
+}
@@ -4,11 +4,26 @@ import "ggami-go/internal/domain"
 
 // Type aliases for backward compatibility
 type FieldDef = domain.FieldDef
+type FieldValidation = domain.FieldValidation
 type ModelDef = domain.ModelDef
+type RelationDef = domain.RelationDef
+type RelationType = domain.RelationType
 type DBType = domain.DBType
 type RolePermission = domain.RolePermission
 type ModelRBAC = domain.ModelRBAC
 type RBACConfig = domain.RBACConfig
+type StorageConfig = domain.StorageConfig
+type S3Config = domain.S3Config
+type MailConfig = domain.MailConfig
+type RateLimitConfig = domain.RateLimitConfig
+type SMTPConfig = domain.SMTPConfig
+type SQLTable = domain.SQLTable
+type SQLColumn = domain.SQLColumn
+type SQLIndex = domain.SQLIndex
+type SQLForeignKey = domain.SQLForeignKey
+type SchemaDiff = domain.SchemaDiff
+type TableDiff = domain.TableDiff
+type ColumnChange = domain.ColumnChange
 
 // Re-export constants
 const (
@@ -16,4 +31,8 @@ const (
 	DBTypePostgres = domain.DBTypePostgres
 	DBTypeMySQL    = domain.DBTypeMySQL
 	DBTypeSQLite   = domain.DBTypeSQLite
+
+	RelationBelongsTo = domain.RelationBelongsTo
+	RelationHasMany   = domain.RelationHasMany
+	RelationMany2Many = domain.RelationMany2Many
 )
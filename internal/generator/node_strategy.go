@@ -2,12 +2,13 @@ package generator
 
 import (
 	"encoding/json"
-	"os"
 	"path/filepath"
 )
 
 // NodeGenerator implements the Generator interface for Node.js projects
-type NodeGenerator struct{}
+type NodeGenerator struct {
+	out OutputFS // nil means disk
+}
 
 func (n *NodeGenerator) Scaffold(path string) error {
 	dirs := []string{
@@ -15,7 +16,7 @@ func (n *NodeGenerator) Scaffold(path string) error {
 		filepath.Join(path, "src"),
 	}
 	for _, dir := range dirs {
-		if err := os.MkdirAll(dir, 0755); err != nil {
+		if err := outputOrDisk(n.out).MkdirAll(dir, 0755); err != nil {
 			return err
 		}
 	}
@@ -39,7 +40,7 @@ func (n *NodeGenerator) CreateManifest(config ProjectConfig) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(filepath.Join(config.TargetPath, "package.json"), data, 0644)
+	return outputOrDisk(n.out).WriteFile(filepath.Join(config.TargetPath, "package.json"), data, 0644)
 }
 
 func (n *NodeGenerator) GenerateCode(config ProjectConfig) error {
@@ -56,5 +57,5 @@ app.listen(port, () => {
   console.log(` + "`" + `Example app listening on port ${port}` + "`" + `)
 });
 `
-	return os.WriteFile(filepath.Join(config.TargetPath, "src", "main.js"), []byte(appJS), 0644)
+	return outputOrDisk(n.out).WriteFile(filepath.Join(config.TargetPath, "src", "main.js"), []byte(appJS), 0644)
 }
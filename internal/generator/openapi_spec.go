@@ -0,0 +1,1009 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// specObject is a JSON object that keeps its keys in insertion order, so the
+// spec lists paths and properties in model and field order
+type specObject struct {
+	keys   []string
+	values map[string]any
+}
+
+func obj(pairs ...any) *specObject {
+	o := &specObject{values: make(map[string]any)}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		o.set(pairs[i].(string), pairs[i+1])
+	}
+	return o
+}
+
+func (o *specObject) set(key string, value any) *specObject {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+	return o
+}
+
+func (o *specObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(k)
+		buf.Write(key)
+		buf.WriteByte(':')
+		value, err := json.Marshal(o.values[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// listPageSize is the default and listMaxPageSize the largest page[size] of
+// the list endpoints (see DefaultSize and MaxSize in query.go.tmpl)
+const (
+	listPageSize    = 20
+	listMaxPageSize = 100
+)
+
+// filterOperators lists the filter operators per query.Kind (see Operators
+// in query.go.tmpl)
+var filterOperators = map[string][]string{
+	"Text":   {"eq", "ne", "in", "nin", "like", "null"},
+	"Number": {"eq", "ne", "gt", "gte", "lt", "lte", "in", "nin", "null"},
+	"Bool":   {"eq", "ne", "null"},
+	"Time":   {"eq", "ne", "gt", "gte", "lt", "lte", "null"},
+}
+
+// apiDescription explains how the generated handlers pick the response format
+const apiDescription = "Create and update routes accept form, multipart and JSON bodies. " +
+	"Requests that send JSON, or accept application/json but not text/html, get JSON: " +
+	"the record (201 with Location, 200), 204 after a delete, or an Error with a message per field (400, 409), 404, 401 and 403. " +
+	"Browsers get HTML forms and redirects."
+
+// BuildOpenAPI renders the OpenAPI 3 document (openapi.json) describing the
+// JSON and form endpoints of every model. With RBAC, model routes require
+// the JWT issued by /api/auth/login as a bearer token or the token cookie,
+// and a role with the route's permission on the model.
+func BuildOpenAPI(data TemplateData) ([]byte, error) {
+	paths := obj()
+	schemas := obj()
+	var tags []any
+
+	if data.HasRBAC {
+		tokens := obj("schema", obj("$ref", "#/components/schemas/Tokens"))
+		tokensContent := obj("application/json", tokens)
+		errorContent := obj("application/json", obj("schema", obj("$ref", "#/components/schemas/Error")))
+		refreshBody := obj("content", obj(
+			"application/x-www-form-urlencoded", obj("schema", obj("$ref", "#/components/schemas/RefreshRequest")),
+			"application/json", obj("schema", obj("$ref", "#/components/schemas/RefreshRequest")),
+		))
+		credentials := obj(
+			"type", "object",
+			"required", []string{"email", "password"},
+			"properties", obj(
+				"email", obj("type", "string", "format", "email"),
+				"password", obj("type", "string", "format", "password"),
+			),
+		)
+		loginDescription := "API clients (Accept: application/json) get the tokens as JSON, browsers as the token and refresh_token cookies. " +
+			"After 5 failed logins of an email, or 20 from a client IP, logins are locked out for 30 seconds, doubled after each further failure up to an hour."
+		loginTokens, loginRedirect := tokens, "Signed in; the token and refresh_token cookies are set"
+		if data.HasTwoFactor {
+			loginDescription += " Users with two-factor authentication get a two_factor_token instead, to send with a code to /api/auth/2fa."
+			loginTokens = obj("schema", obj("oneOf", []any{
+				obj("$ref", "#/components/schemas/Tokens"),
+				obj("$ref", "#/components/schemas/TwoFactorChallenge"),
+			}))
+			loginRedirect += ", or the two_factor_token cookie with a redirect to /login/2fa"
+		}
+		paths.set("/api/auth/login", obj("post", obj(
+			"tags", []string{"auth"},
+			"summary", "Sign in and start a session",
+			"description", loginDescription,
+			"operationId", "login",
+			"security", []any{},
+			"requestBody", obj("required", true, "content", obj(
+				"application/x-www-form-urlencoded", obj("schema", credentials),
+				"application/json", obj("schema", credentials),
+			)),
+			"responses", obj(
+				"200", obj("description", "Signed in (API clients), or the login page with an error message",
+					"content", obj("application/json", loginTokens, "text/html", obj())),
+				"303", obj("description", loginRedirect,
+					"headers", obj("Set-Cookie", obj("schema", obj("type", "string")))),
+				"401", obj("description", "Wrong email or password (API clients)", "content", errorContent),
+				"429", obj("description", "Too many failed logins of the email or client IP",
+					"headers", obj("Retry-After", obj("description", "Seconds until the lockout ends", "schema", obj("type", "integer"))),
+					"content", errorContent),
+			),
+		)))
+		registration := obj(
+			"type", "object",
+			"required", []string{"email", "password"},
+			"properties", obj(
+				"email", obj("type", "string", "format", "email"),
+				"password", obj("type", "string", "format", "password", "minLength", 8),
+			),
+		)
+		paths.set("/api/auth/register", obj("post", obj(
+			"tags", []string{"auth"},
+			"summary", "Create a user with the default role",
+			"description", "A posted role is ignored; admins change roles on the users page.",
+			"operationId", "register",
+			"security", []any{},
+			"requestBody", obj("required", true, "content", obj(
+				"application/x-www-form-urlencoded", obj("schema", registration),
+				"application/json", obj("schema", registration),
+			)),
+			"responses", obj(
+				"201", obj("description", "Registered (API clients)", "content", obj("application/json", obj("schema", obj("$ref", "#/components/schemas/User")))),
+				"303", obj("description", "Registered (browsers); redirects to /login"),
+				"409", obj("description", "The email is taken", "content", obj("application/json", obj("schema", obj("$ref", "#/components/schemas/Error")), "text/html", obj())),
+				"422", obj("description", "Missing or invalid email, or a short password, with an error per field; browsers get the form back",
+					"content", obj("application/json", obj("schema", obj("$ref", "#/components/schemas/Error")), "text/html", obj())),
+			),
+		)))
+		paths.set("/api/auth/refresh", obj("post", obj(
+			"tags", []string{"auth"},
+			"summary", "Trade a refresh token for new tokens",
+			"description", "Each refresh token works once: the response carries its replacement. "+
+				"Using a replaced refresh token again after 30 seconds revokes the session. "+
+				"Without a refresh_token field, the refresh_token cookie is used and the new tokens are set as cookies.",
+			"operationId", "refreshToken",
+			"security", []any{},
+			"requestBody", refreshBody,
+			"responses", obj(
+				"200", obj("description", "New tokens", "content", tokensContent),
+				"204", obj("description", "New token cookies are set"),
+				"401", obj("description", "Unknown, expired, revoked or reused refresh token", "content", errorContent),
+			),
+		)))
+		paths.set("/api/auth/logout", obj("post", obj(
+			"tags", []string{"auth"},
+			"summary", "End the session of a refresh token",
+			"description", "Its access tokens stop working at once. Without a refresh_token field, the refresh_token cookie is used.",
+			"operationId", "logout",
+			"security", []any{},
+			"requestBody", refreshBody,
+			"responses", obj(
+				"204", obj("description", "Signed out (API clients)"),
+				"303", obj("description", "Signed out; redirect to /login"),
+			),
+		)))
+		paths.set("/api/auth/forgot-password", obj("post", obj(
+			"tags", []string{"auth"},
+			"summary", "Mail a single-use password reset link",
+			"description", "The response is the same whether or not the email belongs to a user.",
+			"operationId", "forgotPassword",
+			"security", []any{},
+			"requestBody", obj("required", true, "content", obj(
+				"application/x-www-form-urlencoded", obj("schema", obj(
+					"type", "object",
+					"required", []string{"email"},
+					"properties", obj("email", obj("type", "string", "format", "email")),
+				)),
+			)),
+			"responses", obj(
+				"200", obj("description", "Page saying a link was sent if the email is known", "content", obj("text/html", obj())),
+			),
+		)))
+		paths.set("/api/auth/reset-password", obj("post", obj(
+			"tags", []string{"auth"},
+			"summary", "Set a new password with a mailed reset token",
+			"operationId", "resetPassword",
+			"security", []any{},
+			"requestBody", obj("required", true, "content", obj(
+				"application/x-www-form-urlencoded", obj("schema", obj(
+					"type", "object",
+					"required", []string{"token", "password", "password_confirm"},
+					"properties", obj(
+						"token", obj("type", "string"),
+						"password", obj("type", "string", "format", "password"),
+						"password_confirm", obj("type", "string", "format", "password"),
+					),
+				)),
+			)),
+			"responses", obj(
+				"200", obj("description", "Login page after the change, or the reset page with an error message", "content", obj("text/html", obj())),
+			),
+		)))
+		tags = append(tags, obj("name", "auth"))
+		addUserPaths(paths, data.RBAC.Roles)
+		tags = append(tags, obj("name", "users", "description", "For roles in adminRoles"))
+		if data.HasTwoFactor {
+			addTwoFactorPaths(paths, data.RBAC.Roles)
+			tags = append(tags, obj("name", "two-factor", "description",
+				"Admins pick the roles that must use two-factor authentication. Until their users set it up, "+
+					"other dashboard and model routes answer 403 (API clients) or redirect to /dashboard/profile/2fa."))
+		}
+	}
+
+	for _, m := range data.Models {
+		tag := obj("name", m.Name)
+		if d := scopeDescription(m); d != "" {
+			tag.set("description", d)
+		}
+		tags = append(tags, tag)
+		schemas.set(m.Name, modelSchema(m))
+		schemas.set(m.Name+"Input", inputSchema(m, false))
+		schemas.set(m.Name+"Patch", inputSchema(m, true))
+		addModelPaths(paths, m, data.HasRBAC)
+	}
+	schemas.set("ImportReport", obj(
+		"type", "object",
+		"properties", obj(
+			"created", obj("type", "integer"),
+			"errors", obj("type", "array", "description", "Rows that failed; none is created if any fails", "items", obj(
+				"type", "object",
+				"properties", obj(
+					"line", obj("type", "integer", "description", "Line in the file; the header is line 1"),
+					"fields", obj("type", "object", "description", "Validation message per column", "additionalProperties", obj("type", "string")),
+					"error", obj("type", "string", "description", "Database error on insert"),
+				),
+			)),
+		),
+	))
+	if data.HasRBAC {
+		schemas.set("Tokens", obj(
+			"type", "object",
+			"properties", obj(
+				"access_token", obj("type", "string", "description", "JWT to send as a bearer token"),
+				"refresh_token", obj("type", "string", "description", "Replaces the refresh token sent; absent when a refresh within 30 seconds of another kept it"),
+				"token_type", obj("type", "string", "example", "Bearer"),
+				"expires_in", obj("type", "integer", "description", "Seconds until the access token expires"),
+			),
+		))
+		userProps := obj(
+			"id", obj("type", "integer"),
+			"email", obj("type", "string", "format", "email"),
+			"role", obj("type", "string", "enum", data.RBAC.Roles),
+		)
+		if data.HasTenants {
+			userProps.set("tenant_id", obj("type", "integer"))
+		}
+		if data.HasTwoFactor {
+			userProps.set("two_factor", obj("type", "boolean", "description", "Whether the user set up two-factor authentication"))
+		}
+		schemas.set("User", obj("type", "object", "properties", userProps))
+		if data.HasTwoFactor {
+			schemas.set("TwoFactorChallenge", obj(
+				"type", "object",
+				"properties", obj(
+					"two_factor_token", obj("type", "string", "description", "Send to /api/auth/2fa with a code"),
+					"expires_in", obj("type", "integer", "description", "Seconds until the token expires"),
+				),
+			))
+			schemas.set("TwoFactorStatus", obj(
+				"type", "object",
+				"properties", obj(
+					"enabled", obj("type", "boolean"),
+					"required", obj("type", "boolean", "description", "The user's role must use two-factor authentication"),
+					"recovery_codes_left", obj("type", "integer"),
+					"secret", obj("type", "string", "description", "Base32 key to enter in an authenticator app; only while off"),
+					"otpauth_uri", obj("type", "string", "description", "The key as an otpauth:// URI; only while off"),
+					"setup_token", obj("type", "string", "description", "Send back with the first code; only while off"),
+				),
+			))
+			schemas.set("RecoveryCodes", obj(
+				"type", "object",
+				"properties", obj("recovery_codes", obj("type", "array", "description", "Single-use codes, shown only once", "items", obj("type", "string"))),
+			))
+		}
+		schemas.set("RefreshRequest", obj(
+			"type", "object",
+			"properties", obj("refresh_token", obj("type", "string")),
+		))
+	}
+	schemas.set("Error", obj(
+		"type", "object",
+		"properties", obj(
+			"error", obj("type", "string"),
+			"fields", obj("type", "object", "description", "Validation message per field", "additionalProperties", obj("type", "string")),
+		),
+	))
+
+	spec := obj(
+		"openapi", "3.0.3",
+		"info", obj("title", data.ProjectName+" API", "version", "1.0.0", "description", apiDescription),
+		"tags", tags,
+		"paths", paths,
+		"components", obj("schemas", schemas),
+	)
+	if data.HasRBAC {
+		components := spec.values["components"].(*specObject)
+		components.set("securitySchemes", obj(
+			"bearerAuth", obj("type", "http", "scheme", "bearer", "bearerFormat", "JWT"),
+			"cookieAuth", obj("type", "apiKey", "in", "cookie", "name", "token",
+				"description", "Browsers sending the token cookie must also send the value of the csrf_token cookie "+
+					"as the csrf_token form field or the X-CSRF-Token header with every POST, PUT, PATCH and DELETE, "+
+					"the auth routes included. Requests with an Authorization header or a JSON body are exempt."),
+		))
+		spec.set("security", []any{obj("bearerAuth", []string{}), obj("cookieAuth", []string{})})
+	}
+
+	out, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// scopeDescription says which records of m the signed-in user finds, if m
+// has an owner or tenant; other records answer 404
+func scopeDescription(m ModelTmplData) string {
+	var who, columns []string
+	if m.TenantField != "" {
+		who = append(who, "of their tenant")
+		columns = append(columns, m.TenantColumn)
+	}
+	if m.OwnerField != "" {
+		who = append(who, "that they created, unless their role is in adminRoles")
+		columns = append(columns, m.OwnerColumn)
+	}
+	if len(who) == 0 {
+		return ""
+	}
+	return "Users find only the records " + strings.Join(who, " and ") + "; others answer 404. " +
+		"New records get " + strings.Join(columns, " and ") + " from the signed-in user, never from the request."
+}
+
+// addUserPaths describes the users page of admins, which answers JSON too
+func addUserPaths(paths *specObject, roles []string) {
+	user := obj("$ref", "#/components/schemas/User")
+	errorContent := obj("application/json", obj("schema", obj("$ref", "#/components/schemas/Error")))
+	denied := func(responses *specObject) *specObject {
+		return responses.
+			set("401", obj("description", "Not signed in (API clients)", "content", errorContent)).
+			set("403", obj("description", "The user's role is not in adminRoles", "content", errorContent))
+	}
+	roleSchema := obj(
+		"type", "object",
+		"required", []string{"role"},
+		"properties", obj("role", obj("type", "string", "enum", roles)),
+	)
+
+	paths.set("/dashboard/users", obj("get", obj(
+		"tags", []string{"users"},
+		"summary", "List users and their roles",
+		"operationId", "listUserRoles",
+		"parameters", []any{
+			obj("name", "page", "in", "query", "description", "1-based page of 50 users", "schema", obj("type", "integer", "minimum", 1, "default", 1)),
+		},
+		"responses", denied(obj("200", obj("description", "Users",
+			"content", obj("application/json", obj("schema", obj("type", "array", "items", user)), "text/html", obj())))),
+	)))
+	paths.set("/dashboard/users/{id}/role", obj("post", obj(
+		"tags", []string{"users"},
+		"summary", "Change a user's role",
+		"description", "Signs the user out everywhere, so the new role applies at once. Admins cannot change their own role.",
+		"operationId", "setUserRole",
+		"parameters", []any{obj("name", "id", "in", "path", "required", true, "schema", obj("type", "integer"))},
+		"requestBody", obj("required", true, "content", obj(
+			"application/x-www-form-urlencoded", obj("schema", roleSchema),
+			"application/json", obj("schema", roleSchema),
+		)),
+		"responses", denied(obj(
+			"200", obj("description", "The changed user (API clients)", "content", obj("application/json", obj("schema", user))),
+			"303", obj("description", "Changed (browsers); redirects to /dashboard/users"),
+			"400", obj("description", "Unknown role, or the admin's own user", "content", errorContent),
+			"404", obj("description", "Not found", "content", errorContent),
+		)),
+	)))
+}
+
+// addTwoFactorPaths describes the second login step, the two-factor setup of
+// the profile and the two-factor settings of admins
+func addTwoFactorPaths(paths *specObject, roles []string) {
+	errorContent := obj("application/json", obj("schema", obj("$ref", "#/components/schemas/Error")))
+	status := obj("application/json", obj("schema", obj("$ref", "#/components/schemas/TwoFactorStatus")), "text/html", obj())
+	// failed forms get an Error (API clients) or the page with the message
+	failed := obj("application/json", obj("schema", obj("$ref", "#/components/schemas/Error")), "text/html", obj())
+	recoveryCodes := obj("$ref", "#/components/schemas/RecoveryCodes")
+	codeBody := func(required []string, props *specObject) *specObject {
+		schema := obj("type", "object", "required", required, "properties", props)
+		return obj("required", true, "content", obj(
+			"application/x-www-form-urlencoded", obj("schema", schema),
+			"application/json", obj("schema", schema),
+		))
+	}
+	code := obj("type", "string", "description", "Code of the authenticator app, or a recovery code")
+	signedIn := func(responses *specObject) *specObject {
+		return responses.set("401", obj("description", "Not signed in (API clients)", "content", errorContent))
+	}
+	admin := func(responses *specObject) *specObject {
+		return signedIn(responses).
+			set("403", obj("description", "The user's role is not in adminRoles", "content", errorContent))
+	}
+	rolesSchema := obj(
+		"type", "object",
+		"properties", obj("roles", obj("type", "array", "items", obj("type", "string", "enum", roles))),
+	)
+
+	paths.set("/api/auth/2fa", obj("post", obj(
+		"tags", []string{"auth"},
+		"summary", "Finish a login with a two-factor code",
+		"description", "Without a two_factor_token field, the two_factor_token cookie of the login is used. "+
+			"Each recovery code works once, and each app code once. Invalid codes count as failed logins of the user.",
+		"operationId", "loginTwoFactor",
+		"security", []any{},
+		"requestBody", codeBody([]string{"code"}, obj(
+			"two_factor_token", obj("type", "string"),
+			"code", code,
+		)),
+		"responses", obj(
+			"200", obj("description", "Signed in (API clients), or the code page with an error message",
+				"content", obj("application/json", obj("schema", obj("$ref", "#/components/schemas/Tokens")), "text/html", obj())),
+			"303", obj("description", "Signed in; the token and refresh_token cookies are set. Also sent, to /login, when the token expired"),
+			"401", obj("description", "Invalid code, or an expired two_factor_token (API clients)", "content", errorContent),
+			"429", obj("description", "Too many invalid codes",
+				"headers", obj("Retry-After", obj("description", "Seconds until the lockout ends", "schema", obj("type", "integer"))),
+				"content", errorContent),
+		),
+	)))
+	paths.set("/dashboard/profile/2fa", obj(
+		"get", obj(
+			"tags", []string{"two-factor"},
+			"summary", "Show the user's two-factor status",
+			"description", "While two-factor authentication is off, it carries a new key to set it up with.",
+			"operationId", "getTwoFactor",
+			"responses", signedIn(obj("200", obj("description", "Two-factor status", "content", status))),
+		),
+		"post", obj(
+			"tags", []string{"two-factor"},
+			"summary", "Turn on two-factor authentication",
+			"description", "Takes the setup_token of the status and a code of the key. Signs the user out everywhere else; "+
+				"API clients get new tokens and the recovery codes.",
+			"operationId", "enableTwoFactor",
+			"requestBody", codeBody([]string{"setup_token", "code"}, obj(
+				"setup_token", obj("type", "string"),
+				"code", obj("type", "string", "description", "Code of the authenticator app"),
+			)),
+			"responses", signedIn(obj(
+				"200", obj("description", "Turned on; the recovery codes are shown once",
+					"content", obj("application/json", obj("schema", obj("allOf", []any{
+						obj("$ref", "#/components/schemas/Tokens"),
+						recoveryCodes,
+					})), "text/html", obj())),
+				"400", obj("description", "Invalid code, or an expired setup_token", "content", failed),
+				"409", obj("description", "Already on", "content", failed),
+			)),
+		),
+	))
+	paths.set("/dashboard/profile/2fa/recovery-codes", obj("post", obj(
+		"tags", []string{"two-factor"},
+		"summary", "Replace the user's recovery codes",
+		"operationId", "renewRecoveryCodes",
+		"requestBody", codeBody([]string{"code"}, obj("code", code)),
+		"responses", signedIn(obj(
+			"200", obj("description", "New recovery codes; the old ones no longer work",
+				"content", obj("application/json", obj("schema", recoveryCodes), "text/html", obj())),
+			"400", obj("description", "Invalid code", "content", failed),
+			"409", obj("description", "Two-factor authentication is off", "content", failed),
+			"429", obj("description", "Too many invalid codes", "content", failed),
+		)),
+	)))
+	paths.set("/dashboard/profile/2fa/disable", obj("post", obj(
+		"tags", []string{"two-factor"},
+		"summary", "Turn off two-factor authentication",
+		"operationId", "disableTwoFactor",
+		"requestBody", codeBody([]string{"code"}, obj("code", code)),
+		"responses", signedIn(obj(
+			"204", obj("description", "Turned off (API clients)"),
+			"303", obj("description", "Turned off (browsers); redirects to /dashboard/profile/2fa"),
+			"400", obj("description", "Invalid code", "content", failed),
+			"403", obj("description", "The user's role must use two-factor authentication", "content", failed),
+			"409", obj("description", "Two-factor authentication is off", "content", failed),
+			"429", obj("description", "Too many invalid codes", "content", failed),
+		)),
+	)))
+	paths.set("/dashboard/users/{id}/two-factor/reset", obj("post", obj(
+		"tags", []string{"two-factor"},
+		"summary", "Turn off a user's two-factor authentication",
+		"description", "For users who lost their device and recovery codes. Signs the user out everywhere. "+
+			"Admins turn off their own on their profile.",
+		"operationId", "resetUserTwoFactor",
+		"parameters", []any{obj("name", "id", "in", "path", "required", true, "schema", obj("type", "integer"))},
+		"responses", admin(obj(
+			"200", obj("description", "The user (API clients)", "content", obj("application/json", obj("schema", obj("$ref", "#/components/schemas/User")))),
+			"303", obj("description", "Reset (browsers); redirects to /dashboard/users"),
+			"400", obj("description", "The admin's own user", "content", errorContent),
+			"404", obj("description", "Not found", "content", errorContent),
+		)),
+	)))
+	paths.set("/dashboard/users/two-factor-roles", obj(
+		"get", obj(
+			"tags", []string{"two-factor"},
+			"summary", "List the roles that must use two-factor authentication",
+			"operationId", "getTwoFactorRoles",
+			"responses", admin(obj("200", obj("description", "Roles", "content", obj("application/json", obj("schema", rolesSchema))))),
+		),
+		"post", obj(
+			"tags", []string{"two-factor"},
+			"summary", "Set the roles that must use two-factor authentication",
+			"description", "Replaces the roles; send none to clear them.",
+			"operationId", "setTwoFactorRoles",
+			"requestBody", obj("content", obj(
+				"application/x-www-form-urlencoded", obj("schema", rolesSchema),
+				"application/json", obj("schema", rolesSchema),
+			)),
+			"responses", admin(obj(
+				"200", obj("description", "The roles (API clients)", "content", obj("application/json", obj("schema", rolesSchema))),
+				"303", obj("description", "Set (browsers); redirects to /dashboard/users"),
+				"400", obj("description", "Unknown role", "content", errorContent),
+			)),
+		),
+	))
+}
+
+// addModelPaths describes the API routes main.go mounts for m
+func addModelPaths(paths *specObject, m ModelTmplData, auth bool) {
+	base := "/" + m.NameSnake + "s"
+	ref := obj("$ref", "#/components/schemas/"+m.Name)
+	idParam := obj("name", "id", "in", "path", "required", true, "schema", idSchema(idType(m)))
+	errorContent := obj("application/json", obj("schema", obj("$ref", "#/components/schemas/Error")))
+
+	withAuth := func(responses *specObject) *specObject {
+		if !auth {
+			return responses
+		}
+		const login = "browsers are redirected to /login when no valid token is sent"
+		if r, ok := responses.values["303"].(*specObject); ok {
+			r.set("description", r.values["description"].(string)+"; "+login)
+		} else {
+			responses.set("303", obj("description", "Not signed in; "+login))
+		}
+		responses.set("401", obj("description", "Not signed in (API clients)", "content", errorContent))
+		responses.set("403", obj("description", "The user's role has no permission for this action on "+m.Name, "content", errorContent))
+		return responses
+	}
+	redirect := func(what string) *specObject {
+		return obj("description", what+" (browsers); redirects to the list page ("+base+"/ui/list)")
+	}
+	record := func(what string) *specObject {
+		return obj("description", what, "content", obj("application/json", obj("schema", ref)))
+	}
+	notFound := func() *specObject {
+		return obj("description", "Not found", "content", errorContent)
+	}
+	conflict := func() *specObject {
+		return obj("description", "A unique value is taken, or a foreign key is violated", "content", errorContent)
+	}
+	// written responds like Create and Update: JSON for API clients, a
+	// redirect or the form with errors for browsers
+	written := func(status, what string) *specObject {
+		responses := obj(status, record(what), "303", redirect(what),
+			"400", obj("description", "Invalid body, or validation failed with an error per field", "content", errorContent))
+		if status != "201" {
+			responses.set("404", notFound())
+		}
+		invalid := obj("description", "Validation failed (browsers); the form is returned with field errors", "content", obj("text/html", obj()))
+		if m.HasBelongsTo {
+			invalid = obj("description", "A belongsTo key names a record that does not exist or is not visible (API clients), or validation failed (browsers); the form is returned with field errors",
+				"content", obj("application/json", obj("schema", obj("$ref", "#/components/schemas/Error")), "text/html", obj()))
+		}
+		return responses.set("409", conflict()).set("422", invalid)
+	}
+	deleted := func() *specObject {
+		return obj("204", obj("description", "Deleted (API clients)"), "303", redirect("Deleted"),
+			"404", obj("description", "Not found (API clients)", "content", errorContent), "409", conflict())
+	}
+
+	listParams := func(filters ...any) []any {
+		return append(append([]any{}, filters...),
+			obj("name", "page[number]", "in", "query", "description", "1-based page number (alias: page)", "schema", obj("type", "integer", "minimum", 1, "default", 1)),
+			obj("name", "page[size]", "in", "query", "description", "Page size (alias: per_page)", "schema", obj("type", "integer", "minimum", 1, "maximum", listMaxPageSize, "default", listPageSize)),
+		)
+	}
+	listResponse := func(what string) *specObject {
+		return withAuth(obj("200", obj(
+			"description", what,
+			"headers", obj(
+				"X-Total-Count", obj("description", "Number of matching records", "schema", obj("type", "integer")),
+				"Link", obj("description", "URLs of the first, prev, next and last pages", "schema", obj("type", "string")),
+			),
+			"content", obj("application/json", obj("schema", obj("type", "array", "items", ref))),
+		)))
+	}
+
+	var sortFields []string
+	filters := obj()
+	for _, f := range m.Fields {
+		if f.Filter == "" {
+			continue
+		}
+		sortFields = append(sortFields, f.JsonName)
+		filters.set(f.JsonName, obj(
+			"type", "object",
+			"description", "Operators: "+strings.Join(filterOperators[f.Filter], ", "),
+			"additionalProperties", obj("type", "string"),
+		))
+	}
+
+	filterParams := []any{
+		obj("name", "filter", "in", "query", "style", "deepObject", "explode", true,
+			"description", "filter[field][op]=value; filter[field]=value means eq. in and nin take a comma-separated list, null takes true or false.",
+			"schema", obj("type", "object", "properties", filters)),
+		obj("name", "q", "in", "query", "description", "Search text fields (LIKE)", "schema", obj("type", "string")),
+		obj("name", "sort", "in", "query",
+			"description", "Comma-separated fields, - for descending: -price,name. Fields: "+strings.Join(sortFields, ", "),
+			"schema", obj("type", "string")),
+	}
+
+	deleteSummary := "Delete a " + m.Name
+	if m.SoftDelete {
+		deleteSummary = "Move a " + m.Name + " to the trash"
+	}
+
+	created := written("201", "Created")
+	created.values["201"].(*specObject).set("headers", obj("Location", obj("description", "URL of the new record", "schema", obj("type", "string"))))
+
+	paths.set(base+"/", obj(
+		"get", obj(
+			"tags", []string{m.Name},
+			"summary", "List "+m.NamePlural,
+			"description", "Returns one page of the records that match the filters.",
+			"operationId", "list"+m.Name,
+			"parameters", listParams(filterParams...),
+			"responses", listResponse(m.NamePlural),
+		),
+		"post", obj(
+			"tags", []string{m.Name},
+			"summary", "Create a "+m.Name,
+			"operationId", "create"+m.Name,
+			"requestBody", requestBody(m, false),
+			"responses", withAuth(created),
+		),
+	))
+
+	paths.set(base+"/{id}", obj(
+		"parameters", []any{idParam},
+		"get", obj(
+			"tags", []string{m.Name},
+			"summary", "Get a "+m.Name,
+			"operationId", "get"+m.Name,
+			"responses", withAuth(obj("200", record(m.Name), "404", notFound())),
+		),
+		"put", obj(
+			"tags", []string{m.Name},
+			"summary", "Replace a "+m.Name,
+			"operationId", "update"+m.Name,
+			"requestBody", requestBody(m, false),
+			"responses", withAuth(written("200", "Updated")),
+		),
+		"patch", obj(
+			"tags", []string{m.Name},
+			"summary", "Change the fields of a "+m.Name+" that are sent",
+			"operationId", "patch"+m.Name,
+			"requestBody", requestBody(m, true),
+			"responses", withAuth(written("200", "Updated")),
+		),
+		"delete", obj(
+			"tags", []string{m.Name},
+			"summary", deleteSummary,
+			"operationId", "delete"+m.Name,
+			"responses", withAuth(deleted()),
+		),
+	))
+
+	var columns []string
+	ignored := "id"
+	for _, f := range m.Fields {
+		columns = append(columns, f.JsonName)
+	}
+	if m.Timestamps {
+		ignored += ", created_at, updated_at"
+	}
+	export := func(format, mediaType string) *specObject {
+		return obj("get", obj(
+			"tags", []string{m.Name},
+			"summary", "Download the "+m.NamePlural+" matching the filters as "+format,
+			"description", "Takes the filter, q and sort parameters of the list; every matching record is included. Columns: "+strings.Join(columns, ", "),
+			"operationId", "export"+m.Name+format,
+			"parameters", filterParams,
+			"responses", withAuth(obj(
+				"200", obj("description", m.NamePlural+" as "+format, "content", obj(mediaType, obj("schema", obj("type", "string", "format", "binary")))),
+				"400", obj("description", "Invalid filter or sort", "content", errorContent),
+			)),
+		))
+	}
+	paths.set(base+"/export.csv", export("CSV", "text/csv"))
+	paths.set(base+"/export.xlsx", export("Excel", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"))
+
+	reportContent := obj("application/json", obj("schema", obj("$ref", "#/components/schemas/ImportReport")))
+	importResponses := obj(
+		"201", obj("description", "All rows were created", "content", reportContent),
+		"400", obj("description", "The file cannot be read (Error), or rows failed validation (ImportReport)", "content", obj("application/json", obj("schema", obj("oneOf", []any{
+			obj("$ref", "#/components/schemas/ImportReport"),
+			obj("$ref", "#/components/schemas/Error"),
+		})))),
+		"409", obj("description", "Rows failed only on unique values or foreign keys", "content", reportContent),
+	)
+	if m.HasBelongsTo {
+		importResponses.set("422", obj("description", "Rows failed on belongsTo keys naming records that do not exist or are not visible", "content", reportContent))
+	}
+	paths.set(base+"/import", obj("post", obj(
+		"tags", []string{m.Name},
+		"summary", "Create "+m.NamePlural+" from a CSV file",
+		"description", "The header row names export columns; "+ignored+" and file columns are ignored. Rows are validated like the create form and inserted in one transaction, so nothing is created if a row fails. Browsers get a preview page and post the csv text back with confirm to insert.",
+		"operationId", "import"+m.Name,
+		"requestBody", obj("required", true, "content", obj(
+			"multipart/form-data", obj("schema", obj(
+				"type", "object",
+				"properties", obj(
+					"file", obj("type", "string", "format", "binary", "description", "UTF-8 CSV file"),
+					"csv", obj("type", "string", "description", "CSV text, if no file is sent"),
+					"confirm", obj("type", "string", "description", "Insert the rows (browsers); API clients always insert"),
+				),
+			)),
+		)),
+		"responses", withAuth(importResponses),
+	)))
+
+	if m.HasFile {
+		var fileFields, imageFields []string
+		for _, f := range m.FileFields {
+			fileFields = append(fileFields, f.JsonName)
+			if f.Kind == "image" {
+				imageFields = append(imageFields, f.JsonName)
+			}
+		}
+		download := func(summary, id string, fields []string) *specObject {
+			return obj(
+				"parameters", []any{idParam, obj("name", "field", "in", "path", "required", true, "schema", obj("type", "string", "enum", fields))},
+				"get", obj(
+					"tags", []string{m.Name},
+					"summary", summary,
+					"description", "Images and PDFs are sent inline, other files as attachments. Range requests are supported.",
+					"operationId", id,
+					"responses", withAuth(obj(
+						"200", obj("description", "The file", "content", obj("*/*", obj("schema", obj("type", "string", "format", "binary")))),
+						"404", obj("description", "No such record or file", "content", errorContent),
+					)),
+				),
+			)
+		}
+		paths.set(base+"/{id}/files/{field}", download("Download a file of a "+m.Name, "download"+m.Name+"File", fileFields))
+		if m.HasImage {
+			paths.set(base+"/{id}/files/{field}/thumb", download("Download the JPEG thumbnail of an image of a "+m.Name, "download"+m.Name+"Thumbnail", imageFields))
+		}
+	}
+
+	if m.SoftDelete {
+		inTrash := func() *specObject {
+			return obj("description", "Not in the trash", "content", errorContent)
+		}
+		purged := func() *specObject {
+			return obj("204", obj("description", "Deleted for good (API clients)"),
+				"303", obj("description", "Deleted for good (browsers); redirects to the trash ("+base+"/ui/trash)"),
+				"404", inTrash(), "409", conflict())
+		}
+		paths.set(base+"/trash", obj("get", obj(
+			"tags", []string{m.Name},
+			"summary", "List the "+m.NamePlural+" in the trash",
+			"description", "Takes the parameters of the list.",
+			"operationId", "listTrashed"+m.Name,
+			"parameters", listParams(filterParams...),
+			"responses", listResponse("Deleted "+m.NamePlural),
+		)))
+		paths.set(base+"/{id}/restore", obj(
+			"parameters", []any{idParam},
+			"post", obj(
+				"tags", []string{m.Name},
+				"summary", "Restore a "+m.Name+" from the trash",
+				"operationId", "restore"+m.Name,
+				"responses", withAuth(obj(
+					"200", record("Restored"),
+					"303", obj("description", "Restored (browsers); redirects to the trash ("+base+"/ui/trash)"),
+					"404", inTrash(),
+				)),
+			),
+		))
+		paths.set(base+"/{id}/purge", obj(
+			"parameters", []any{idParam},
+			"delete", obj(
+				"tags", []string{m.Name},
+				"summary", "Delete a "+m.Name+" in the trash for good",
+				"operationId", "purge"+m.Name,
+				"responses", withAuth(purged()),
+			),
+			"post", obj(
+				"tags", []string{m.Name},
+				"summary", "Delete a "+m.Name+" in the trash for good (form alias of DELETE)",
+				"operationId", "purge"+m.Name+"Form",
+				"responses", withAuth(purged()),
+			),
+		))
+	}
+
+	// HTML forms cannot send PUT or DELETE
+	paths.set(base+"/{id}/update", obj(
+		"parameters", []any{idParam},
+		"post", obj(
+			"tags", []string{m.Name},
+			"summary", "Replace a "+m.Name+" (form alias of PUT)",
+			"operationId", "update"+m.Name+"Form",
+			"requestBody", requestBody(m, false),
+			"responses", withAuth(written("200", "Updated")),
+		),
+	))
+	paths.set(base+"/{id}/delete", obj(
+		"parameters", []any{idParam},
+		"post", obj(
+			"tags", []string{m.Name},
+			"summary", deleteSummary+" (form alias of DELETE)",
+			"operationId", "delete"+m.Name+"Form",
+			"responses", withAuth(deleted()),
+		),
+	))
+}
+
+// requestBody is the body of the create and update routes, which read form
+// values or JSON; a partial (PATCH) body has no required fields
+func requestBody(m ModelTmplData, partial bool) *specObject {
+	formType := "application/x-www-form-urlencoded"
+	if m.HasFile {
+		formType = "multipart/form-data"
+	}
+	schema := obj("$ref", "#/components/schemas/"+m.Name+"Input")
+	if partial {
+		schema = obj("$ref", "#/components/schemas/"+m.Name+"Patch")
+	}
+	return obj("required", true, "content", obj(
+		"application/json", obj("schema", schema),
+		formType, obj("schema", schema),
+	))
+}
+
+// modelSchema describes the JSON encoding of m
+func modelSchema(m ModelTmplData) *specObject {
+	props := obj()
+	for _, f := range m.Fields {
+		s := fieldSchema(f)
+		if f.IsID || f.IsAuto {
+			s.set("readOnly", true)
+		}
+		if f.Nullable {
+			s.set("nullable", true)
+		}
+		props.set(f.JsonName, s)
+	}
+	if m.SoftDelete {
+		props.set("deleted_at", obj("type", "string", "format", "date-time", "nullable", true, "readOnly", true,
+			"description", "When the record was moved to the trash"))
+	}
+	for _, r := range m.Relations {
+		target := obj("$ref", "#/components/schemas/"+r.Model)
+		if r.IsBelongsTo {
+			props.set(r.JsonName, target)
+		} else {
+			props.set(r.JsonName, obj("type", "array", "items", target))
+		}
+	}
+	return obj("type", "object", "properties", props)
+}
+
+// inputSchema describes the values the create and update handlers read; in a
+// partial (PATCH) body every field is optional
+func inputSchema(m ModelTmplData, partial bool) *specObject {
+	props := obj()
+	var required []string
+	for _, f := range m.Fields {
+		if f.IsID || f.IsAuto {
+			continue
+		}
+		s := inputFieldSchema(f)
+		if f.Nullable {
+			s.set("nullable", true)
+			s.set("description", "Empty or null leaves it unset")
+		}
+		if v := f.Rules; v != nil {
+			if v.Required && !partial {
+				required = append(required, f.JsonName)
+			}
+			if v.Min != nil {
+				s.set("minimum", *v.Min)
+			}
+			if v.Max != nil {
+				s.set("maximum", *v.Max)
+			}
+			if v.MinLength > 0 {
+				s.set("minLength", v.MinLength)
+			}
+			if v.MaxLength > 0 {
+				s.set("maxLength", v.MaxLength)
+			}
+			if v.Pattern != "" {
+				s.set("pattern", anchoredPattern(v.Pattern))
+			}
+			if v.Email {
+				s.set("format", "email")
+			}
+			if v.Unique {
+				s.set("description", "Must be unique")
+			}
+		}
+		props.set(f.JsonName, s)
+	}
+	for _, r := range m.Relations {
+		if r.IsMany2Many {
+			props.set(r.FormName, obj(
+				"type", "array",
+				"description", "IDs of the related "+r.Model+" records; replaces the current selection",
+				"items", idSchema(r.TargetIDType),
+			))
+		}
+	}
+	s := obj("type", "object", "properties", props)
+	if len(required) > 0 {
+		s.set("required", required)
+	}
+	return s
+}
+
+// fieldSchema describes how a field is encoded in JSON responses
+func fieldSchema(f FieldTmplData) *specObject {
+	switch f.Kind {
+	case "int":
+		return obj("type", "integer", "format", "int64")
+	case "uint":
+		return obj("type", "integer", "format", "int64", "minimum", 0)
+	case "float64":
+		return obj("type", "number", "format", "double")
+	case "decimal":
+		return obj("type", "string", "format", "decimal", "example", "12.50")
+	case "bool":
+		return obj("type", "boolean")
+	case "time.Time", "date":
+		return obj("type", "string", "format", "date-time")
+	case "enum":
+		return obj("type", "string", "enum", f.EnumValues)
+	case "uuid":
+		return obj("type", "string", "format", "uuid")
+	case "json":
+		return obj("description", "Any JSON value")
+	case "file", "image":
+		return obj("type", "string", "description", "Storage key of the upload; GET {id}/files/"+f.JsonName+" downloads it")
+	}
+	return obj("type", "string")
+}
+
+// inputFieldSchema describes the form or JSON value a field's parse code accepts
+func inputFieldSchema(f FieldTmplData) *specObject {
+	switch f.Kind {
+	case "decimal":
+		return obj("type", "number")
+	case "bool":
+		return obj("type", "boolean", "description", `"true" or "on" when checked`)
+	case "time.Time":
+		return obj("type", "string", "example", "2024-01-31T09:30", "description", "Local time, YYYY-MM-DDTHH:MM, or RFC 3339")
+	case "date":
+		return obj("type", "string", "format", "date", "description", "YYYY-MM-DD, or RFC 3339")
+	case "json":
+		return obj("description", "Any JSON value; JSON text in forms")
+	case "file", "image":
+		return obj("type", "string", "format", "binary", "description", "Multipart bodies only")
+	}
+	return fieldSchema(f)
+}
+
+// idSchema describes a primary key of Go type goType in paths and forms
+func idSchema(goType string) *specObject {
+	if strings.HasPrefix(goType, "uint") || strings.HasPrefix(goType, "int") {
+		return obj("type", "integer", "format", "int64")
+	}
+	if goType == "uuid.UUID" {
+		return obj("type", "string", "format", "uuid")
+	}
+	return obj("type", "string")
+}
@@ -0,0 +1,122 @@
+package generator
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DiskFS writes generated files to the real filesystem
+type DiskFS struct{}
+
+func (DiskFS) MkdirAll(path string, perm fs.FileMode) error { return os.MkdirAll(path, perm) }
+
+func (DiskFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+func (DiskFS) ReadFile(name string) ([]byte, error) { return os.ReadFile(name) }
+
+// Walk calls fn for every regular file under root in lexical order
+func (DiskFS) Walk(root string, fn func(path string, data []byte) error) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return fn(path, data)
+	})
+}
+
+// MemFS keeps generated files in memory (used for dry runs).
+// Like the OS, writing a file requires its parent directory to exist.
+type MemFS struct {
+	mu    sync.Mutex
+	dirs  map[string]bool
+	files map[string][]byte
+}
+
+// NewMemFS creates an empty in-memory filesystem
+func NewMemFS() *MemFS {
+	return &MemFS{dirs: map[string]bool{}, files: map[string][]byte{}}
+}
+
+func (m *MemFS) MkdirAll(path string, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for p := filepath.Clean(path); ; p = filepath.Dir(p) {
+		if _, isFile := m.files[p]; isFile {
+			return &fs.PathError{Op: "mkdir", Path: p, Err: fs.ErrExist}
+		}
+		m.dirs[p] = true
+		if parent := filepath.Dir(p); parent == p {
+			break
+		}
+	}
+	return nil
+}
+
+func (m *MemFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	name = filepath.Clean(name)
+	if !m.dirs[filepath.Dir(name)] {
+		return &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	if m.dirs[name] {
+		return &fs.PathError{Op: "open", Path: name, Err: fs.ErrExist}
+	}
+	m.files[name] = append([]byte(nil), data...)
+	return nil
+}
+
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.files[filepath.Clean(name)]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// Walk calls fn for every file under root in lexical order
+func (m *MemFS) Walk(root string, fn func(path string, data []byte) error) error {
+	m.mu.Lock()
+	root = filepath.Clean(root)
+	var names []string
+	for name := range m.files {
+		if name == root || strings.HasPrefix(name, root+string(filepath.Separator)) {
+			names = append(names, name)
+		}
+	}
+	m.mu.Unlock()
+
+	sort.Strings(names)
+	for _, name := range names {
+		data, err := m.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		if err := fn(name, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func outputOrDisk(out OutputFS) OutputFS {
+	if out == nil {
+		return DiskFS{}
+	}
+	return out
+}
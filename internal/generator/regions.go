@@ -0,0 +1,130 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// Protected regions let users keep hand-written code inside generated files.
+// Templates emit named, empty regions:
+//
+//	// ggami:begin custom-imports
+//	// ggami:end
+//
+// or, in HTML templates, <!-- ggami:begin extra-fields --> ... <!-- ggami:end -->.
+// On regeneration the bodies found in the existing file are copied into the
+// freshly rendered one.
+
+var (
+	regionBeginRe = regexp.MustCompile(`^\s*(?://|<!--)\s*ggami:begin\s+([A-Za-z0-9_.-]+)\s*(?:-->)?\s*$`)
+	regionEndRe   = regexp.MustCompile(`^\s*(?://|<!--)\s*ggami:end\s*(?:-->)?\s*$`)
+	regionMarker  = []byte("ggami:begin")
+)
+
+// HasRegions reports whether src contains any protected region markers
+func HasRegions(src []byte) bool {
+	return bytes.Contains(src, regionMarker)
+}
+
+// ExtractRegions returns the body of every protected region in src, keyed by name.
+// A body is the exact text between the begin and end marker lines.
+func ExtractRegions(src []byte) (map[string]string, error) {
+	regions := make(map[string]string)
+	lines := splitLines(src)
+
+	open := ""
+	var body bytes.Buffer
+	for i, line := range lines {
+		trimmed := bytes.TrimRight(line, "\r\n")
+		if m := regionBeginRe.FindSubmatch(trimmed); m != nil {
+			if open != "" {
+				return nil, fmt.Errorf("line %d: region %q starts inside region %q", i+1, m[1], open)
+			}
+			open = string(m[1])
+			if _, dup := regions[open]; dup {
+				return nil, fmt.Errorf("line %d: duplicate region %q", i+1, open)
+			}
+			body.Reset()
+			continue
+		}
+		if regionEndRe.Match(trimmed) {
+			if open == "" {
+				return nil, fmt.Errorf("line %d: ggami:end without matching begin", i+1)
+			}
+			regions[open] = body.String()
+			open = ""
+			continue
+		}
+		if open != "" {
+			body.Write(line)
+		}
+	}
+	if open != "" {
+		return nil, fmt.Errorf("region %q is not closed", open)
+	}
+	return regions, nil
+}
+
+// MergeRegions replaces the bodies of the regions in generated with the
+// bodies from previous. Regions of previous that no longer exist in the
+// generated output are returned as orphans so callers can report them.
+func MergeRegions(generated []byte, previous map[string]string) ([]byte, []string, error) {
+	var out bytes.Buffer
+	used := make(map[string]bool)
+
+	open := ""
+	for i, line := range splitLines(generated) {
+		trimmed := bytes.TrimRight(line, "\r\n")
+		if m := regionBeginRe.FindSubmatch(trimmed); m != nil {
+			if open != "" {
+				return nil, nil, fmt.Errorf("line %d: region %q starts inside region %q", i+1, m[1], open)
+			}
+			open = string(m[1])
+			out.Write(line)
+			if body, ok := previous[open]; ok {
+				out.WriteString(body)
+				used[open] = true
+			}
+			continue
+		}
+		if regionEndRe.Match(trimmed) {
+			open = ""
+			out.Write(line)
+			continue
+		}
+		// Template defaults inside a region are dropped when the user's body wins
+		if open != "" && used[open] {
+			continue
+		}
+		out.Write(line)
+	}
+	if open != "" {
+		return nil, nil, fmt.Errorf("region %q is not closed", open)
+	}
+
+	var orphans []string
+	for name, body := range previous {
+		if !used[name] && len(bytes.TrimSpace([]byte(body))) > 0 {
+			orphans = append(orphans, name)
+		}
+	}
+	sort.Strings(orphans)
+	return out.Bytes(), orphans, nil
+}
+
+// splitLines splits src into lines, keeping line terminators
+func splitLines(src []byte) [][]byte {
+	var lines [][]byte
+	for len(src) > 0 {
+		i := bytes.IndexByte(src, '\n')
+		if i < 0 {
+			lines = append(lines, src)
+			break
+		}
+		lines = append(lines, src[:i+1])
+		src = src[i+1:]
+	}
+	return lines
+}
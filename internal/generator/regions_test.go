@@ -0,0 +1,126 @@
+package generator
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+const goRegions = `package handlers
+
+import (
+	"net/http"
+	// ggami:begin custom-imports
+	// ggami:end
+)
+
+func routes() {
+	// ggami:begin custom-routes
+	route("/ping")
+	// ggami:end
+}
+`
+
+const htmlRegions = `<form>
+  <input name="title">
+  <!-- ggami:begin extra-fields -->
+  <!-- ggami:end -->
+</form>
+`
+
+func TestExtractRegions(t *testing.T) {
+	tests := []struct {
+		name, src string
+		want      map[string]string
+		err       string
+	}{
+		{"go", goRegions, map[string]string{"custom-imports": "", "custom-routes": "\troute(\"/ping\")\n"}, ""},
+		{"html", htmlRegions, map[string]string{"extra-fields": ""}, ""},
+		{"crlf", "// ggami:begin a\r\nx\r\n// ggami:end\r\n", map[string]string{"a": "x\r\n"}, ""},
+		{"no regions", "package x\n", map[string]string{}, ""},
+		{"unclosed", "// ggami:begin a\nx\n", nil, `region "a" is not closed`},
+		{"nested", "// ggami:begin a\n// ggami:begin b\n// ggami:end\n", nil, `line 2: region "b" starts inside region "a"`},
+		{"end without begin", "x\n<!-- ggami:end -->\n", nil, "line 2: ggami:end without matching begin"},
+		{"duplicate", "// ggami:begin a\n// ggami:end\n// ggami:begin a\n// ggami:end\n", nil, `line 3: duplicate region "a"`},
+	}
+	for _, tt := range tests {
+		got, err := ExtractRegions([]byte(tt.src))
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("%s: error %v, want %q", tt.name, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: regions %q, want %q", tt.name, got, tt.want)
+		}
+		for name, body := range tt.want {
+			if got[name] != body {
+				t.Errorf("%s: region %s = %q, want %q", tt.name, name, got[name], body)
+			}
+		}
+	}
+}
+
+func TestMergeRegions(t *testing.T) {
+	tests := []struct {
+		name      string
+		generated string
+		previous  map[string]string
+		want      string
+		orphans   []string
+	}{
+		{
+			name:      "go body replaces the template default",
+			generated: goRegions,
+			previous: map[string]string{
+				"custom-imports": "\t\"strings\"\n",
+				"custom-routes":  "\troute(\"/health\")\n",
+			},
+			want: strings.NewReplacer(
+				"// ggami:begin custom-imports\n", "// ggami:begin custom-imports\n\t\"strings\"\n",
+				"\troute(\"/ping\")\n", "\troute(\"/health\")\n",
+			).Replace(goRegions),
+		},
+		{
+			name:      "html",
+			generated: htmlRegions,
+			previous:  map[string]string{"extra-fields": "  <input name=\"note\">\n"},
+			want:      strings.Replace(htmlRegions, "-->\n", "-->\n  <input name=\"note\">\n", 1),
+		},
+		{
+			name:      "region missing from the old file keeps its default",
+			generated: goRegions,
+			previous:  map[string]string{"custom-imports": "\t\"strings\"\n"},
+			want:      strings.Replace(goRegions, "custom-imports\n", "custom-imports\n\t\"strings\"\n", 1),
+		},
+		{
+			name:      "renamed region is reported",
+			generated: htmlRegions,
+			previous:  map[string]string{"fields": "  <input name=\"note\">\n", "empty": "\n"},
+			want:      htmlRegions,
+			orphans:   []string{"fields"},
+		},
+	}
+	for _, tt := range tests {
+		got, orphans, err := MergeRegions([]byte(tt.generated), tt.previous)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s:\n got %q\nwant %q", tt.name, got, tt.want)
+		}
+		if !slices.Equal(orphans, tt.orphans) {
+			t.Errorf("%s: orphans %q, want %q", tt.name, orphans, tt.orphans)
+		}
+	}
+
+	if _, _, err := MergeRegions([]byte("<!-- ggami:begin a -->\n"), nil); err == nil {
+		t.Error("unclosed region in the generated file: no error")
+	}
+}
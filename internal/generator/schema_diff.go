@@ -0,0 +1,482 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// widening lists kind changes every existing value survives
+var widening = map[[2]string]bool{
+	{"int", "float64"}:    true,
+	{"int", "decimal"}:    true,
+	{"uint", "float64"}:   true,
+	{"uint", "decimal"}:   true,
+	{"bool", "int"}:       true,
+	{"bool", "uint"}:      true,
+	{"string", "text"}:    true,
+	{"enum", "string"}:    true,
+	{"enum", "text"}:      true,
+	{"uuid", "string"}:    true,
+	{"uuid", "text"}:      true,
+	{"file", "text"}:      true,
+	{"file", "image"}:     true,
+	{"image", "file"}:     true,
+	{"image", "text"}:     true,
+	{"json", "text"}:      true,
+	{"date", "time.Time"}: true,
+}
+
+// DiffConfigs compares the schemas two project configs generate
+func DiffConfigs(old, new ProjectConfig) SchemaDiff {
+	return DiffSchema(BuildSQLSchema(old), BuildSQLSchema(new))
+}
+
+// DiffSchema compares two schemas table by table and flags the changes
+// that can lose data
+func DiffSchema(old, new []SQLTable) SchemaDiff {
+	var diff SchemaDiff
+	oldByName := tablesByName(old)
+	newByName := tablesByName(new)
+
+	for _, t := range new {
+		o, ok := oldByName[t.Name]
+		if !ok {
+			diff.AddedTables = append(diff.AddedTables, t)
+			continue
+		}
+		if td, changed := diffTable(o, t); changed {
+			diff.ChangedTables = append(diff.ChangedTables, td)
+		}
+	}
+	for _, t := range old {
+		if _, ok := newByName[t.Name]; !ok {
+			diff.RemovedTables = append(diff.RemovedTables, t)
+		}
+	}
+
+	diff.Destructive = destructiveChanges(diff)
+	return diff
+}
+
+func diffTable(old, new SQLTable) (TableDiff, bool) {
+	td := TableDiff{Table: new.Name, Model: new.Model}
+
+	oldCols := make(map[string]SQLColumn)
+	for _, c := range old.Columns {
+		oldCols[c.Name] = c
+	}
+	newCols := make(map[string]bool)
+	for _, c := range new.Columns {
+		newCols[c.Name] = true
+		o, ok := oldCols[c.Name]
+		switch {
+		case !ok:
+			td.AddedColumns = append(td.AddedColumns, c)
+		case !sameColumn(o, c):
+			td.ChangedColumns = append(td.ChangedColumns, ColumnChange{Old: o, New: c, Narrowing: narrowing(o, c)})
+		}
+	}
+	for _, c := range old.Columns {
+		if !newCols[c.Name] {
+			td.RemovedColumns = append(td.RemovedColumns, c)
+		}
+	}
+
+	td.AddedIndexes, td.RemovedIndexes = diffByName(old.Indexes, new.Indexes, func(i SQLIndex) string { return i.Name })
+	td.AddedForeignKeys, td.RemovedForeignKeys = diffByName(old.ForeignKeys, new.ForeignKeys, func(fk SQLForeignKey) string { return fk.Name })
+
+	changed := len(td.AddedColumns)+len(td.RemovedColumns)+len(td.ChangedColumns)+
+		len(td.AddedIndexes)+len(td.RemovedIndexes)+len(td.AddedForeignKeys)+len(td.RemovedForeignKeys) > 0 ||
+		!reflect.DeepEqual(old.PrimaryKey, new.PrimaryKey)
+	return td, changed
+}
+
+// diffByName matches items by name; an item whose definition changed is
+// reported as removed and added again
+func diffByName[T any](old, new []T, name func(T) string) (added, removed []T) {
+	oldByName := make(map[string]T)
+	for _, o := range old {
+		oldByName[name(o)] = o
+	}
+	newByName := make(map[string]T)
+	for _, n := range new {
+		newByName[name(n)] = n
+		if o, ok := oldByName[name(n)]; !ok || !reflect.DeepEqual(o, n) {
+			added = append(added, n)
+		}
+	}
+	for _, o := range old {
+		if n, ok := newByName[name(o)]; !ok || !reflect.DeepEqual(o, n) {
+			removed = append(removed, o)
+		}
+	}
+	return added, removed
+}
+
+// sameColumn reports whether two columns render identically in every dialect
+func sameColumn(a, b SQLColumn) bool {
+	for _, db := range SQLDialects {
+		d := SQLDialect{DB: db}
+		if d.ColumnDef(a) != d.ColumnDef(b) {
+			return false
+		}
+	}
+	return true
+}
+
+// narrowing reports whether existing values may not fit the new column type
+func narrowing(old, new SQLColumn) bool {
+	if old.RawType != new.RawType {
+		return true // unknown types, assume the worst
+	}
+	if old.Kind != new.Kind && !widening[[2]string{old.Kind, new.Kind}] {
+		return true
+	}
+	if sizedKind(old.Kind) && sizedKind(new.Kind) {
+		return columnSize(new) < columnSize(old)
+	}
+	return false
+}
+
+func sizedKind(kind string) bool {
+	for _, typ := range lookupFieldTypeOrString(kind).SQLTypes {
+		if strings.Contains(typ, "%d") {
+			return true
+		}
+	}
+	return false
+}
+
+func columnSize(c SQLColumn) int {
+	if c.Size <= 0 {
+		return 255
+	}
+	return c.Size
+}
+
+// destructiveChanges describes the changes that need explicit confirmation
+func destructiveChanges(diff SchemaDiff) []string {
+	var out []string
+	for _, t := range diff.RemovedTables {
+		out = append(out, "drop table "+t.Name+describeModel(t.Model)+" and all its rows")
+	}
+	for _, td := range diff.ChangedTables {
+		for _, c := range td.RemovedColumns {
+			out = append(out, fmt.Sprintf("drop column %s.%s and its values", td.Table, c.Name))
+		}
+		for _, ch := range td.ChangedColumns {
+			if ch.Narrowing {
+				out = append(out, fmt.Sprintf("narrow column %s.%s from %s to %s", td.Table, ch.New.Name, describeType(ch.Old), describeType(ch.New)))
+			}
+			if ch.New.NotNull && !ch.Old.NotNull {
+				out = append(out, fmt.Sprintf("make column %s.%s NOT NULL (fails on existing NULLs)", td.Table, ch.New.Name))
+			}
+		}
+		for _, c := range td.AddedColumns {
+			if c.NotNull && c.Default == "" && !c.PrimaryKey {
+				out = append(out, fmt.Sprintf("add NOT NULL column %s.%s without a default (fails on existing rows)", td.Table, c.Name))
+			}
+		}
+		for _, idx := range td.AddedIndexes {
+			if idx.Unique {
+				out = append(out, fmt.Sprintf("add unique index %s on %s (fails on existing duplicates)", idx.Name, td.Table))
+			}
+		}
+	}
+	return out
+}
+
+func describeModel(model string) string {
+	if model == "" {
+		return ""
+	}
+	return " (model " + model + ")"
+}
+
+func describeType(c SQLColumn) string {
+	if c.RawType != "" {
+		return c.RawType
+	}
+	if sizedKind(c.Kind) {
+		return fmt.Sprintf("%s(%d)", c.Kind, columnSize(c))
+	}
+	return c.Kind
+}
+
+func tablesByName(tables []SQLTable) map[string]SQLTable {
+	m := make(map[string]SQLTable)
+	for _, t := range tables {
+		m[t.Name] = t
+	}
+	return m
+}
+
+// AlterMigration turns the difference between two schemas into a migration.
+// Down reverts Up by migrating from new back to old.
+func AlterMigration(d SQLDialect, version string, old, new []SQLTable) Migration {
+	diff := DiffSchema(old, new)
+	m := Migration{Version: version, Name: migrationName(diff)}
+	m.Up, m.UpNotes = d.alterStatements(old, new)
+	m.Down, m.DownNotes = d.alterStatements(new, old)
+	return m
+}
+
+// migrationName summarizes a diff for the migration file name
+func migrationName(diff SchemaDiff) string {
+	switch {
+	case len(diff.AddedTables) == 1 && len(diff.RemovedTables) == 0 && len(diff.ChangedTables) == 0:
+		return "create_" + diff.AddedTables[0].Name
+	case len(diff.AddedTables) == 0 && len(diff.RemovedTables) == 1 && len(diff.ChangedTables) == 0:
+		return "drop_" + diff.RemovedTables[0].Name
+	case len(diff.AddedTables) == 0 && len(diff.RemovedTables) == 0 && len(diff.ChangedTables) == 1:
+		td := diff.ChangedTables[0]
+		switch {
+		case len(td.AddedColumns) == 1 && len(td.RemovedColumns) == 0 && len(td.ChangedColumns) == 0:
+			return "add_" + td.Table + "_" + td.AddedColumns[0].Name
+		case len(td.AddedColumns) == 0 && len(td.RemovedColumns) == 1 && len(td.ChangedColumns) == 0:
+			return "drop_" + td.Table + "_" + td.RemovedColumns[0].Name
+		}
+		return "alter_" + td.Table
+	}
+	return "alter_schema"
+}
+
+// alterStatements renders the statements migrating from one schema to
+// another, plus notes on changes that have to be applied by hand
+func (d SQLDialect) alterStatements(from, to []SQLTable) ([]string, []string) {
+	diff := DiffSchema(from, to)
+	fromByName := tablesByName(from)
+	toByName := tablesByName(to)
+	var stmts, notes []string
+
+	// SQLite cannot alter columns or constraints in place
+	rebuild := make(map[string]bool)
+	if d.DB == DBTypeSQLite {
+		for _, td := range diff.ChangedTables {
+			rebuild[td.Table] = sqliteNeedsRebuild(td) || !reflect.DeepEqual(fromByName[td.Table].PrimaryKey, toByName[td.Table].PrimaryKey)
+		}
+	}
+
+	// Constraints and indexes that go away
+	for _, td := range diff.ChangedTables {
+		if rebuild[td.Table] {
+			continue
+		}
+		for _, fk := range td.RemovedForeignKeys {
+			stmts = append(stmts, d.DropForeignKey(td.Table, fk))
+		}
+		for _, idx := range td.RemovedIndexes {
+			stmts = append(stmts, d.DropIndex(td.Table, idx))
+		}
+	}
+
+	// Removed tables, dependents first
+	removed := make(map[string]bool)
+	for _, t := range diff.RemovedTables {
+		removed[t.Name] = true
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		if removed[from[i].Name] {
+			stmts = append(stmts, d.DropTable(from[i].Name))
+		}
+	}
+
+	// Added tables, referenced tables first
+	created, _ := d.createTables(diff.AddedTables)
+	stmts = append(stmts, created...)
+
+	// Column changes
+	for _, td := range diff.ChangedTables {
+		if rebuild[td.Table] {
+			stmts = append(stmts, d.rebuildTable(fromByName[td.Table], toByName[td.Table])...)
+			continue
+		}
+		for _, c := range td.RemovedColumns {
+			stmts = append(stmts, d.DropColumn(td.Table, c))
+		}
+		for _, ch := range td.ChangedColumns {
+			s, n := d.AlterColumn(td.Table, ch)
+			stmts = append(stmts, s...)
+			notes = append(notes, n...)
+		}
+		for _, c := range td.AddedColumns {
+			stmts = append(stmts, d.AddColumn(td.Table, c))
+		}
+		if !reflect.DeepEqual(fromByName[td.Table].PrimaryKey, toByName[td.Table].PrimaryKey) {
+			notes = append(notes, fmt.Sprintf("primary key of %s changed; update it by hand", td.Table))
+		}
+	}
+
+	// New indexes and constraints
+	for _, td := range diff.ChangedTables {
+		if rebuild[td.Table] {
+			continue
+		}
+		for _, idx := range td.AddedIndexes {
+			stmts = append(stmts, d.CreateIndex(td.Table, idx))
+		}
+		for _, fk := range td.AddedForeignKeys {
+			stmts = append(stmts, d.AddForeignKey(td.Table, fk))
+		}
+	}
+	return stmts, notes
+}
+
+// sqliteNeedsRebuild reports whether a SQLite table has to be copied into a
+// new table; ALTER TABLE there only adds plain columns
+func sqliteNeedsRebuild(td TableDiff) bool {
+	if len(td.RemovedColumns) > 0 || len(td.ChangedColumns) > 0 ||
+		len(td.AddedForeignKeys) > 0 || len(td.RemovedForeignKeys) > 0 {
+		return true
+	}
+	for _, c := range td.AddedColumns {
+		if c.PrimaryKey || c.Unique || (c.NotNull && c.Default == "") {
+			return true
+		}
+	}
+	return false
+}
+
+// rebuildTable copies a SQLite table into one with the new definition
+func (d SQLDialect) rebuildTable(from, to SQLTable) []string {
+	tmp := to
+	tmp.Name = to.Name + "__ggami_new"
+	tmp.Indexes = nil
+
+	toCols := make(map[string]bool)
+	for _, c := range to.Columns {
+		toCols[c.Name] = true
+	}
+	var common []string
+	for _, c := range from.Columns {
+		if toCols[c.Name] {
+			common = append(common, c.Name)
+		}
+	}
+
+	stmts := d.CreateTable(tmp)
+	if len(common) > 0 {
+		stmts = append(stmts, "INSERT INTO "+d.Quote(tmp.Name)+" ("+d.quoteList(common)+")\nSELECT "+d.quoteList(common)+" FROM "+d.Quote(from.Name))
+	}
+	stmts = append(stmts,
+		d.DropTable(from.Name),
+		"ALTER TABLE "+d.Quote(tmp.Name)+" RENAME TO "+d.Quote(to.Name),
+	)
+	for _, idx := range to.Indexes {
+		stmts = append(stmts, d.CreateIndex(to.Name, idx))
+	}
+	return stmts
+}
+
+// AddColumn renders ALTER TABLE ... ADD COLUMN
+func (d SQLDialect) AddColumn(table string, c SQLColumn) string {
+	if d.DB == DBTypeMSSQL {
+		return "ALTER TABLE " + d.Quote(table) + " ADD " + d.ColumnDef(c)
+	}
+	return "ALTER TABLE " + d.Quote(table) + " ADD COLUMN " + d.ColumnDef(c)
+}
+
+// DropColumn renders ALTER TABLE ... DROP COLUMN
+func (d SQLDialect) DropColumn(table string, c SQLColumn) string {
+	return "ALTER TABLE " + d.Quote(table) + " DROP COLUMN " + d.Quote(c.Name)
+}
+
+// AlterColumn renders the statements changing a column in place (not
+// SQLite, which rebuilds the table) and notes what it cannot change
+func (d SQLDialect) AlterColumn(table string, ch ColumnChange) ([]string, []string) {
+	old, c := ch.Old, ch.New
+	t := d.Quote(table)
+	col := d.Quote(c.Name)
+	var stmts, notes []string
+
+	if old.PrimaryKey != c.PrimaryKey || old.AutoIncrement != c.AutoIncrement {
+		notes = append(notes, fmt.Sprintf("primary key / auto increment of %s.%s changed; update it by hand", table, c.Name))
+	}
+
+	switch d.DB {
+	case DBTypePostgres:
+		if d.ColumnType(old) != d.ColumnType(c) {
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s::%s", t, col, d.ColumnType(c), col, d.ColumnType(c)))
+		}
+		if old.NotNull != c.NotNull && !c.PrimaryKey {
+			if c.NotNull {
+				stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL", t, col))
+			} else {
+				stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL", t, col))
+			}
+		}
+		if old.Default != c.Default {
+			if c.Default != "" {
+				stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s", t, col, d.literal(c.Kind, c.Default)))
+			} else {
+				stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT", t, col))
+			}
+		}
+		if old.Unique != c.Unique {
+			// PostgreSQL names column UNIQUE constraints <table>_<column>_key
+			key := d.Quote(table + "_" + c.Name + "_key")
+			if c.Unique {
+				stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s UNIQUE (%s)", t, key, col))
+			} else {
+				stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", t, key))
+			}
+		}
+
+	case DBTypeMySQL:
+		plain := c
+		plain.Unique = false
+		stmts = append(stmts, "ALTER TABLE "+t+" MODIFY COLUMN "+d.ColumnDef(plain))
+		if old.Unique != c.Unique {
+			// MySQL names column UNIQUE indexes after the column
+			if c.Unique {
+				stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ADD UNIQUE (%s)", t, col))
+			} else {
+				stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s DROP INDEX %s", t, col))
+			}
+		}
+
+	case DBTypeMSSQL:
+		if d.ColumnType(old) != d.ColumnType(c) || old.NotNull != c.NotNull {
+			null := " NULL"
+			if c.NotNull || c.PrimaryKey {
+				null = " NOT NULL"
+			}
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s%s", t, col, d.ColumnType(c), null))
+		}
+		if old.Default != c.Default {
+			notes = append(notes, fmt.Sprintf("default of %s.%s changed; SQL Server default constraints have generated names, update it by hand", table, c.Name))
+		}
+		if old.Unique != c.Unique {
+			if c.Unique {
+				stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s UNIQUE (%s)", t, d.Quote("uq_"+table+"_"+c.Name), col))
+			} else {
+				notes = append(notes, fmt.Sprintf("drop the UNIQUE constraint on %s.%s by hand", table, c.Name))
+			}
+		}
+	}
+	return stmts, notes
+}
+
+// DropIndex renders DROP INDEX
+func (d SQLDialect) DropIndex(table string, idx SQLIndex) string {
+	switch d.DB {
+	case DBTypeMySQL, DBTypeMSSQL:
+		return "DROP INDEX " + d.Quote(idx.Name) + " ON " + d.Quote(table)
+	default:
+		return "DROP INDEX " + d.Quote(idx.Name)
+	}
+}
+
+// AddForeignKey renders ALTER TABLE ... ADD CONSTRAINT ... FOREIGN KEY
+func (d SQLDialect) AddForeignKey(table string, fk SQLForeignKey) string {
+	return "ALTER TABLE " + d.Quote(table) + " ADD " + d.foreignKey(fk)
+}
+
+// DropForeignKey renders the statement removing a foreign key constraint
+func (d SQLDialect) DropForeignKey(table string, fk SQLForeignKey) string {
+	if d.DB == DBTypeMySQL {
+		return "ALTER TABLE " + d.Quote(table) + " DROP FOREIGN KEY " + d.Quote(fk.Name)
+	}
+	return "ALTER TABLE " + d.Quote(table) + " DROP CONSTRAINT " + d.Quote(fk.Name)
+}
@@ -0,0 +1,132 @@
+package generator
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNarrowing(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new SQLColumn
+		want     bool
+	}{
+		{"shorter string", SQLColumn{Kind: "string", Size: 255}, SQLColumn{Kind: "string", Size: 20}, true},
+		{"default size to shorter", SQLColumn{Kind: "string"}, SQLColumn{Kind: "string", Size: 100}, true},
+		{"longer string", SQLColumn{Kind: "string", Size: 20}, SQLColumn{Kind: "string", Size: 255}, false},
+		{"string to text", SQLColumn{Kind: "string", Size: 255}, SQLColumn{Kind: "text"}, false},
+		{"text to string", SQLColumn{Kind: "text"}, SQLColumn{Kind: "string"}, true},
+		{"int to string", SQLColumn{Kind: "int"}, SQLColumn{Kind: "string"}, true},
+		{"int to float", SQLColumn{Kind: "int"}, SQLColumn{Kind: "float64"}, false},
+		{"float to int", SQLColumn{Kind: "float64"}, SQLColumn{Kind: "int"}, true},
+		{"bool to int", SQLColumn{Kind: "bool"}, SQLColumn{Kind: "int"}, false},
+		{"date to time", SQLColumn{Kind: "date"}, SQLColumn{Kind: "time.Time"}, false},
+		{"time to date", SQLColumn{Kind: "time.Time"}, SQLColumn{Kind: "date"}, true},
+		{"raw type changed", SQLColumn{Kind: "string", RawType: "citext"}, SQLColumn{Kind: "string"}, true},
+	}
+	for _, tt := range tests {
+		if got := narrowing(tt.old, tt.new); got != tt.want {
+			t.Errorf("%s: narrowing = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// shopConfig has a Product model with the given extra fields and, if
+// withOrders is set, an Order model
+func shopConfig(withOrders bool, fields ...FieldDef) ProjectConfig {
+	config := ProjectConfig{
+		ProjectName: "shop",
+		GormMode:    true,
+		Models: []ModelDef{{Name: "Product", Fields: append([]FieldDef{
+			{Name: "ID", Type: "uint", GormTags: []string{"primaryKey"}},
+		}, fields...)}},
+	}
+	if withOrders {
+		config.Models = append(config.Models, ModelDef{Name: "Order", Fields: []FieldDef{
+			{Name: "ID", Type: "uint", GormTags: []string{"primaryKey"}},
+			{Name: "Total", Type: "int"},
+		}})
+	}
+	return config
+}
+
+func TestDiffConfigsDestructive(t *testing.T) {
+	name := FieldDef{Name: "Name", Type: "string", GormTags: []string{"size:255"}}
+	code := FieldDef{Name: "Code", Type: "int"}
+	tests := []struct {
+		name     string
+		old, new ProjectConfig
+		want     []string
+	}{
+		{
+			name: "unchanged",
+			old:  shopConfig(true, name, code),
+			new:  shopConfig(true, name, code),
+		},
+		{
+			name: "additions are safe",
+			old:  shopConfig(false, name),
+			new:  shopConfig(true, name, code, FieldDef{Name: "Note", Type: "text"}),
+		},
+		{
+			name: "widened columns are safe",
+			old:  shopConfig(false, FieldDef{Name: "Name", Type: "string", GormTags: []string{"size:20"}}, code),
+			new:  shopConfig(false, FieldDef{Name: "Name", Type: "text"}, FieldDef{Name: "Code", Type: "float64"}),
+		},
+		{
+			name: "narrowed columns",
+			old:  shopConfig(false, name, code),
+			new:  shopConfig(false, FieldDef{Name: "Name", Type: "string", GormTags: []string{"size:20"}}, FieldDef{Name: "Code", Type: "string"}),
+			want: []string{
+				"narrow column products.name from string(255) to string(20)",
+				"narrow column products.code from int to string(255)",
+			},
+		},
+		{
+			name: "dropped column",
+			old:  shopConfig(false, name, code),
+			new:  shopConfig(false, name),
+			want: []string{"drop column products.code and its values"},
+		},
+		{
+			name: "dropped table",
+			old:  shopConfig(true, name),
+			new:  shopConfig(false, name),
+			want: []string{"drop table orders (model Order) and all its rows"},
+		},
+		{
+			name: "new NOT NULL column without a default",
+			old:  shopConfig(false, name),
+			new:  shopConfig(false, name, FieldDef{Name: "Code", Type: "int", GormTags: []string{"not null"}}),
+			want: []string{"add NOT NULL column products.code without a default (fails on existing rows)"},
+		},
+		{
+			name: "unique rule on a required field",
+			old:  shopConfig(false, name),
+			new: shopConfig(false, FieldDef{Name: "Name", Type: "string", GormTags: []string{"size:255"},
+				Validation: &FieldValidation{Required: true, Unique: true}}),
+			want: []string{"add unique index idx_products_name on products (fails on existing duplicates)"},
+		},
+		{
+			name: "unique rule on an optional field is left to the handlers",
+			old:  shopConfig(false, name),
+			new: shopConfig(false, FieldDef{Name: "Name", Type: "string", GormTags: []string{"size:255"},
+				Validation: &FieldValidation{Unique: true}}),
+		},
+		{
+			name: "column made NOT NULL",
+			old:  shopConfig(false, name),
+			new:  shopConfig(false, FieldDef{Name: "Name", Type: "string", GormTags: []string{"size:255", "not null"}}),
+			want: []string{"make column products.name NOT NULL (fails on existing NULLs)"},
+		},
+	}
+	for _, tt := range tests {
+		diff := DiffConfigs(tt.old, tt.new)
+		if !slices.Equal(diff.Destructive, tt.want) {
+			t.Errorf("%s: destructive %q, want %q", tt.name, diff.Destructive, tt.want)
+		}
+		if tt.name == "unchanged" && !diff.Empty() {
+			t.Errorf("unchanged: diff %+v, want empty", diff)
+		}
+	}
+}
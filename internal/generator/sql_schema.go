@@ -0,0 +1,504 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm/schema"
+)
+
+// gormNaming matches the table and column names GORM derives at runtime
+var gormNaming = schema.NamingStrategy{}
+
+// SQLDialects lists the dialects migrations are generated for
+var SQLDialects = []DBType{DBTypeSQLite, DBTypePostgres, DBTypeMySQL, DBTypeMSSQL}
+
+// BuildSQLSchema derives the tables of a GORM project, ordered so that every
+// table comes after the tables its foreign keys reference
+func BuildSQLSchema(config ProjectConfig) []SQLTable {
+	data := buildTemplateData(config)
+	models := append(data.Models, builtinModels(data)...)
+
+	tableOf := make(map[string]string)
+	idOf := make(map[string]FieldTmplData)
+	for _, m := range models {
+		tableOf[m.Name] = gormNaming.TableName(m.Name)
+		if m.TableName != "" {
+			tableOf[m.Name] = m.TableName
+		}
+		for _, f := range m.Fields {
+			if f.IsID {
+				idOf[m.Name] = f
+			}
+		}
+	}
+
+	var tables []SQLTable
+	var joins []SQLTable
+	for _, m := range models {
+		t := SQLTable{Name: tableOf[m.Name], Model: m.Name}
+		for _, f := range m.Fields {
+			col, idx := sqlColumn(t.Name, f)
+			t.Columns = append(t.Columns, col)
+			t.Indexes = append(t.Indexes, idx...)
+		}
+		if m.SoftDelete {
+			col, idx := sqlColumn(t.Name, FieldTmplData{Name: "DeletedAt", Kind: "time.Time", GormTag: "index"})
+			t.Columns = append(t.Columns, col)
+			t.Indexes = append(t.Indexes, idx...)
+		}
+
+		for _, rel := range m.Relations {
+			switch {
+			case rel.IsBelongsTo:
+				col := columnOf(t, rel.ForeignKey)
+				if hasForeignKey(t, col) {
+					continue
+				}
+				t.ForeignKeys = append(t.ForeignKeys, SQLForeignKey{
+					Name:      "fk_" + t.Name + "_" + col,
+					Column:    col,
+					RefTable:  tableOf[rel.Model],
+					RefColumn: idColumn(idOf[rel.Model]),
+				})
+			case rel.IsMany2Many:
+				joins = append(joins, joinTable(rel.JoinTable, m.Name, tableOf[m.Name], idOf[m.Name], rel.Model, tableOf[rel.Model], idOf[rel.Model]))
+			}
+		}
+		tables = append(tables, t)
+	}
+
+	return append(sortByDependency(tables), joins...)
+}
+
+// builtinModels returns models the generator adds on its own, shaped like
+// their templates (models/user.go, models/password_reset.go and
+// models/session.go for RBAC, models/recovery_code.go and
+// models/two_factor_role.go for two-factor authentication,
+// models/audit_log.go for audit logs)
+func builtinModels(data TemplateData) []ModelTmplData {
+	var models []ModelTmplData
+	add := func(name string, fields ...FieldDef) {
+		m := ModelTmplData{Name: name}
+		for _, f := range fields {
+			m.Fields = append(m.Fields, newFieldTmplData(name, f))
+		}
+		models = append(models, m)
+	}
+	// tenant IDs of users and audit log rows, when models have a tenant
+	var tenant []FieldDef
+	if data.HasTenants {
+		tenant = append(tenant, FieldDef{Name: "TenantID", Type: "uint", GormTags: []string{"index"}})
+	}
+	if data.HasRBAC {
+		fields := append([]FieldDef{
+			{Name: "ID", Type: "uint", GormTags: []string{"primaryKey"}},
+			{Name: "Email", Type: "string", GormTags: []string{"uniqueIndex", "not null"}},
+			{Name: "PasswordHash", Type: "string", GormTags: []string{"not null"}},
+			{Name: "Role", Type: "string", GormTags: []string{"not null", "default:viewer"}},
+		}, tenant...)
+		if data.HasTwoFactor {
+			fields = append(fields,
+				FieldDef{Name: "TOTPSecret", Type: "string", GormTags: []string{"size:255"}},
+				FieldDef{Name: "TOTPStep", Type: "int", GormTags: []string{"not null", "default:0"}},
+			)
+		}
+		add("User", fields...)
+	}
+	if data.HasRBAC {
+		add("PasswordReset",
+			FieldDef{Name: "ID", Type: "uint", GormTags: []string{"primaryKey"}},
+			FieldDef{Name: "CreatedAt", Type: "time.Time"},
+			FieldDef{Name: "UserID", Type: "uint", GormTags: []string{"not null", "index"}},
+			FieldDef{Name: "TokenHash", Type: "string", GormTags: []string{"size:64", "not null", "uniqueIndex"}},
+			FieldDef{Name: "ExpiresAt", Type: "time.Time", GormTags: []string{"not null"}},
+		)
+		add("Session",
+			FieldDef{Name: "ID", Type: "uint", GormTags: []string{"primaryKey"}},
+			FieldDef{Name: "CreatedAt", Type: "time.Time"},
+			FieldDef{Name: "UserID", Type: "uint", GormTags: []string{"not null", "index"}},
+			FieldDef{Name: "TokenHash", Type: "string", GormTags: []string{"size:64", "not null", "uniqueIndex"}},
+			FieldDef{Name: "PrevTokenHash", Type: "string", GormTags: []string{"size:64", "index"}},
+			FieldDef{Name: "UserAgent", Type: "string", GormTags: []string{"size:255"}},
+			FieldDef{Name: "IP", Type: "string", GormTags: []string{"size:64"}},
+			FieldDef{Name: "RefreshedAt", Type: "time.Time", GormTags: []string{"not null"}},
+			FieldDef{Name: "ExpiresAt", Type: "time.Time", GormTags: []string{"not null"}},
+			FieldDef{Name: "RevokedAt", Type: "time.Time", GormTags: []string{"index"}},
+		)
+	}
+	if data.HasTwoFactor {
+		add("RecoveryCode",
+			FieldDef{Name: "ID", Type: "uint", GormTags: []string{"primaryKey"}},
+			FieldDef{Name: "CreatedAt", Type: "time.Time"},
+			FieldDef{Name: "UserID", Type: "uint", GormTags: []string{"not null", "index"}},
+			FieldDef{Name: "CodeHash", Type: "string", GormTags: []string{"size:64", "not null", "index"}},
+			FieldDef{Name: "UsedAt", Type: "time.Time"},
+		)
+		add("TwoFactorRole", append([]FieldDef{
+			{Name: "ID", Type: "uint", GormTags: []string{"primaryKey"}},
+		}, append(tenant, FieldDef{Name: "Role", Type: "string", GormTags: []string{"size:64", "not null"}})...)...)
+	}
+	if data.HasAudit {
+		fields := append([]FieldDef{
+			{Name: "ID", Type: "uint", GormTags: []string{"primaryKey"}},
+			{Name: "CreatedAt", Type: "time.Time", GormTags: []string{"index"}},
+			{Name: "UserID", Type: "uint", GormTags: []string{"index"}},
+		}, tenant...)
+		add("AuditLog", append(fields,
+			FieldDef{Name: "Model", Type: "string", GormTags: []string{"size:64", "not null", "index"}},
+			FieldDef{Name: "RecordID", Type: "string", GormTags: []string{"size:64", "not null", "index"}},
+			FieldDef{Name: "Action", Type: "string", GormTags: []string{"size:16", "not null"}},
+			FieldDef{Name: "Changes", Type: "text"},
+		)...)
+	}
+	return models
+}
+
+// sqlColumn converts a field and its GORM tags into a column and its indexes
+func sqlColumn(table string, f FieldTmplData) (SQLColumn, []SQLIndex) {
+	ft := lookupFieldTypeOrString(f.Kind)
+	col := SQLColumn{
+		Name:    gormNaming.ColumnName("", f.Name),
+		Field:   f.Name,
+		Kind:    ft.Name,
+		Size:    ft.Size,
+		Default: f.DefaultVal,
+	}
+	var indexes []SQLIndex
+
+	for _, tag := range strings.Split(f.GormTag, ";") {
+		tag = strings.TrimSpace(tag)
+		key, value, _ := strings.Cut(tag, ":")
+		switch strings.ToLower(key) {
+		case "primarykey":
+			col.PrimaryKey = true
+		case "autoincrement":
+			col.AutoIncrement = true
+		case "not null":
+			col.NotNull = true
+		case "unique":
+			col.Unique = true
+		case "size":
+			if n, err := strconv.Atoi(value); err == nil {
+				col.Size = n
+			}
+		case "type":
+			if tag != ft.GormTag {
+				col.RawType = value
+			}
+		case "column":
+			col.Name = value
+		case "default":
+			col.Default = strings.Trim(value, "'")
+		case "index":
+			indexes = append(indexes, SQLIndex{Name: "idx_" + table + "_" + col.Name, Columns: []string{col.Name}})
+		case "uniqueindex":
+			indexes = append(indexes, SQLIndex{Name: "idx_" + table + "_" + col.Name, Columns: []string{col.Name}, Unique: true})
+		}
+	}
+	if col.PrimaryKey && (col.Kind == "int" || col.Kind == "uint") {
+		col.AutoIncrement = true
+	}
+	// The database backs the unique rule of required fields; optional ones
+	// are left to the handlers, as their empty values would collide
+	if v := f.Rules; v != nil && v.Unique && v.Required && !col.Unique {
+		if len(indexes) == 0 {
+			indexes = append(indexes, SQLIndex{Name: "idx_" + table + "_" + col.Name, Columns: []string{col.Name}})
+		}
+		indexes[len(indexes)-1].Unique = true
+	}
+	return col, indexes
+}
+
+// joinTable builds a many2many join table with GORM's column names
+func joinTable(name, owner, ownerTable string, ownerID FieldTmplData, target, targetTable string, targetID FieldTmplData) SQLTable {
+	ownerCol := gormNaming.ColumnName("", owner+"ID")
+	targetCol := gormNaming.ColumnName("", target+"ID")
+	return SQLTable{
+		Name: name,
+		Columns: []SQLColumn{
+			{Name: ownerCol, Kind: lookupFieldTypeOrString(ownerID.Kind).Name, NotNull: true},
+			{Name: targetCol, Kind: lookupFieldTypeOrString(targetID.Kind).Name, NotNull: true},
+		},
+		PrimaryKey: []string{ownerCol, targetCol},
+		ForeignKeys: []SQLForeignKey{
+			{Name: "fk_" + name + "_" + ownerCol, Column: ownerCol, RefTable: ownerTable, RefColumn: idColumn(ownerID), OnDelete: "CASCADE"},
+			{Name: "fk_" + name + "_" + targetCol, Column: targetCol, RefTable: targetTable, RefColumn: idColumn(targetID), OnDelete: "CASCADE"},
+		},
+	}
+}
+
+// columnOf returns the column of a model field
+func columnOf(t SQLTable, field string) string {
+	for _, c := range t.Columns {
+		if c.Field == field {
+			return c.Name
+		}
+	}
+	return gormNaming.ColumnName("", field)
+}
+
+// idColumn returns the column of a primary key field
+func idColumn(id FieldTmplData) string {
+	if id.Name == "" {
+		return "id"
+	}
+	col, _ := sqlColumn("", id)
+	return col.Name
+}
+
+func hasForeignKey(t SQLTable, column string) bool {
+	for _, fk := range t.ForeignKeys {
+		if fk.Column == column {
+			return true
+		}
+	}
+	return false
+}
+
+// sortByDependency orders tables so referenced tables come first. Relation
+// validation rejects belongsTo cycles; self references are ignored.
+func sortByDependency(tables []SQLTable) []SQLTable {
+	byName := make(map[string]SQLTable)
+	for _, t := range tables {
+		byName[t.Name] = t
+	}
+	done := make(map[string]bool)
+	var sorted []SQLTable
+	var visit func(t SQLTable)
+	visit = func(t SQLTable) {
+		if done[t.Name] {
+			return
+		}
+		done[t.Name] = true
+		for _, fk := range t.ForeignKeys {
+			if ref, ok := byName[fk.RefTable]; ok && ref.Name != t.Name {
+				visit(ref)
+			}
+		}
+		sorted = append(sorted, t)
+	}
+	for _, t := range tables {
+		visit(t)
+	}
+	return sorted
+}
+
+// SQLDialect renders schema changes for one database
+type SQLDialect struct {
+	DB DBType
+}
+
+// Quote quotes an identifier
+func (d SQLDialect) Quote(name string) string {
+	switch d.DB {
+	case DBTypeMySQL:
+		return "`" + name + "`"
+	case DBTypeMSSQL:
+		return "[" + name + "]"
+	default:
+		return `"` + name + `"`
+	}
+}
+
+// ColumnType returns the column's SQL type
+func (d SQLDialect) ColumnType(c SQLColumn) string {
+	if c.RawType != "" {
+		return c.RawType
+	}
+	typ := lookupFieldTypeOrString(c.Kind).SQLTypes[d.DB]
+	if strings.Contains(typ, "%d") {
+		size := c.Size
+		if size <= 0 {
+			size = 255
+		}
+		typ = fmt.Sprintf(typ, size)
+	}
+	return typ
+}
+
+// ColumnDef renders a column definition for CREATE TABLE / ADD COLUMN
+func (d SQLDialect) ColumnDef(c SQLColumn) string {
+	name := d.Quote(c.Name)
+	if c.PrimaryKey && c.AutoIncrement {
+		switch d.DB {
+		case DBTypeSQLite:
+			return name + " INTEGER PRIMARY KEY AUTOINCREMENT"
+		case DBTypePostgres:
+			return name + " BIGSERIAL PRIMARY KEY"
+		case DBTypeMySQL:
+			return name + " " + d.ColumnType(c) + " NOT NULL AUTO_INCREMENT PRIMARY KEY"
+		case DBTypeMSSQL:
+			return name + " " + d.ColumnType(c) + " IDENTITY(1,1) PRIMARY KEY"
+		}
+	}
+
+	def := name + " " + d.ColumnType(c)
+	if c.PrimaryKey {
+		def += " PRIMARY KEY"
+	} else if c.NotNull {
+		def += " NOT NULL"
+	}
+	if c.Unique {
+		def += " UNIQUE"
+	}
+	if c.Default != "" {
+		def += " DEFAULT " + d.literal(c.Kind, c.Default)
+	}
+	return def
+}
+
+// literal renders a default value for a column of the given kind
+func (d SQLDialect) literal(kind, value string) string {
+	ft := lookupFieldTypeOrString(kind)
+	switch {
+	case kind == "bool":
+		truthy := value == "true" || value == "1"
+		if d.DB == DBTypeSQLite || d.DB == DBTypeMSSQL {
+			if truthy {
+				return "1"
+			}
+			return "0"
+		}
+		if truthy {
+			return "TRUE"
+		}
+		return "FALSE"
+	case ft.Numeric:
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			return value
+		}
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// CreateTable renders CREATE TABLE and CREATE INDEX statements
+func (d SQLDialect) CreateTable(t SQLTable) []string {
+	var defs []string
+	for _, c := range t.Columns {
+		defs = append(defs, d.ColumnDef(c))
+	}
+	if len(t.PrimaryKey) > 0 {
+		defs = append(defs, "PRIMARY KEY ("+d.quoteList(t.PrimaryKey)+")")
+	}
+	for _, fk := range t.ForeignKeys {
+		defs = append(defs, d.foreignKey(fk))
+	}
+
+	stmts := []string{"CREATE TABLE " + d.Quote(t.Name) + " (\n\t" + strings.Join(defs, ",\n\t") + "\n)"}
+	for _, idx := range t.Indexes {
+		stmts = append(stmts, d.CreateIndex(t.Name, idx))
+	}
+	return stmts
+}
+
+// DropTable renders DROP TABLE
+func (d SQLDialect) DropTable(name string) string {
+	return "DROP TABLE " + d.Quote(name)
+}
+
+// CreateIndex renders CREATE [UNIQUE] INDEX
+func (d SQLDialect) CreateIndex(table string, idx SQLIndex) string {
+	unique := ""
+	if idx.Unique {
+		unique = "UNIQUE "
+	}
+	return "CREATE " + unique + "INDEX " + d.Quote(idx.Name) + " ON " + d.Quote(table) + " (" + d.quoteList(idx.Columns) + ")"
+}
+
+func (d SQLDialect) foreignKey(fk SQLForeignKey) string {
+	s := "CONSTRAINT " + d.Quote(fk.Name) + " FOREIGN KEY (" + d.Quote(fk.Column) + ") REFERENCES " +
+		d.Quote(fk.RefTable) + " (" + d.Quote(fk.RefColumn) + ")"
+	if fk.OnDelete != "" {
+		s += " ON DELETE " + fk.OnDelete
+	}
+	return s
+}
+
+func (d SQLDialect) quoteList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = d.Quote(n)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// Migration is one numbered pair of up/down scripts
+type Migration struct {
+	Version   string // "0001"
+	Name      string // "create_schema"
+	Up        []string
+	Down      []string
+	UpNotes   []string // changes Up cannot apply; done by hand
+	DownNotes []string // changes Down cannot apply; done by hand
+}
+
+// FileName returns the migration file name for direction "up" or "down"
+func (m Migration) FileName(direction string) string {
+	return m.Version + "_" + m.Name + "." + direction + ".sql"
+}
+
+// InitialMigration creates every table of the schema
+func InitialMigration(d SQLDialect, tables []SQLTable) Migration {
+	m := Migration{Version: "0001", Name: "create_schema"}
+	var deferred []SQLTable
+	m.Up, deferred = d.createTables(tables)
+	for _, t := range deferred {
+		for _, fk := range t.ForeignKeys {
+			m.Down = append(m.Down, d.DropForeignKey(t.Name, fk))
+		}
+	}
+	for i := len(tables) - 1; i >= 0; i-- {
+		m.Down = append(m.Down, d.DropTable(tables[i].Name))
+	}
+	return m
+}
+
+// createTables renders CREATE TABLE for tables in dependency order. A key
+// referencing a table created further on, which only a cycle through an
+// optional belongsTo allows, is added by ALTER TABLE once every table exists
+// and returned in deferred. SQLite resolves references lazily and cannot add
+// constraints later, so it keeps them inline.
+func (d SQLDialect) createTables(tables []SQLTable) (stmts []string, deferred []SQLTable) {
+	pending := make(map[string]bool)
+	for _, t := range tables {
+		pending[t.Name] = true
+	}
+	for _, t := range tables {
+		delete(pending, t.Name)
+		if d.DB != DBTypeSQLite {
+			later := SQLTable{Name: t.Name}
+			var now []SQLForeignKey
+			for _, fk := range t.ForeignKeys {
+				if pending[fk.RefTable] {
+					later.ForeignKeys = append(later.ForeignKeys, fk)
+				} else {
+					now = append(now, fk)
+				}
+			}
+			if len(later.ForeignKeys) > 0 {
+				t.ForeignKeys = now
+				deferred = append(deferred, later)
+			}
+		}
+		stmts = append(stmts, d.CreateTable(t)...)
+	}
+	for _, t := range deferred {
+		for _, fk := range t.ForeignKeys {
+			stmts = append(stmts, d.AddForeignKey(t.Name, fk))
+		}
+	}
+	return stmts, deferred
+}
+
+// RenderSQL joins statements into a migration script. Every statement ends
+// with ";" at the end of a line, which is how the generated runner splits them.
+func RenderSQL(header string, stmts []string) []byte {
+	var b strings.Builder
+	b.WriteString("-- " + header + "\n")
+	for _, s := range stmts {
+		b.WriteString("\n" + s + ";\n")
+	}
+	return []byte(b.String())
+}
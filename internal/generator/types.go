@@ -4,6 +4,7 @@ import "ggami-go/internal/domain"
 
 // Type aliases for backward compatibility
 type ProjectConfig = domain.ProjectConfig
+type OutputFS = domain.OutputFS
 
 // Generator interface defines the contract for code generators
 type Generator interface {
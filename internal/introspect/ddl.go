@@ -0,0 +1,858 @@
+package introspect
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"ggami-go/internal/domain"
+)
+
+// ImportDDL parses CREATE TABLE, CREATE INDEX and ALTER TABLE ... ADD
+// statements of a T-SQL, PostgreSQL, MySQL or SQLite script and maps the
+// tables to models. dbType may be empty to guess the dialect from the
+// script. Statements and clauses that are not understood are skipped with a
+// warning; the script never fails to import.
+func ImportDDL(script string, dbType domain.DBType) *domain.ModelImport {
+	tables, warnings := ParseDDL(script, dbType)
+	result := ToModels(tables)
+	result.Warnings = append(warnings, result.Warnings...)
+	return &result
+}
+
+// ParseDDL parses the tables a DDL script creates
+func ParseDDL(script string, dbType domain.DBType) ([]domain.SQLTable, []string) {
+	if dbType == "" {
+		dbType = GuessDialect(script)
+	}
+	p := &ddlParser{src: script, dbType: dbType, byName: make(map[string]*ddlTable)}
+	for _, stmt := range splitStatements(script, lex(script)) {
+		p.statement(stmt)
+	}
+	for _, apply := range p.deferred {
+		apply()
+	}
+	return p.finish()
+}
+
+// --- Lexer ---
+
+type tokenKind int
+
+const (
+	tokIdent  tokenKind = iota // keyword or identifier; quoted identifiers have quoted set
+	tokString                  // 'text', N'text', E'text'
+	tokNumber
+	tokPunct
+)
+
+type token struct {
+	kind       tokenKind
+	text       string // identifier or string contents, punctuation
+	quoted     bool   // "x", `x` or [x]
+	start, end int    // byte offsets in the script
+}
+
+// lex splits a script into tokens, dropping whitespace and comments
+func lex(src string) []token {
+	var toks []token
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			i++
+		case strings.HasPrefix(src[i:], "--"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += end + 4
+			}
+		case c == '\'' || strings.ContainsRune("NnEeXxBb", rune(c)) && i+1 < len(src) && src[i+1] == '\'':
+			if c != '\'' {
+				i++
+			}
+			text, n := quotedText(src[i:], '\'', '\'')
+			i += n
+			toks = append(toks, token{kind: tokString, text: text, start: start, end: i})
+		case c == '"' || c == '`' || c == '[':
+			closing := map[byte]byte{'"': '"', '`': '`', '[': ']'}[c]
+			text, n := quotedText(src[i:], c, closing)
+			i += n
+			toks = append(toks, token{kind: tokIdent, text: text, quoted: true, start: start, end: i})
+		case isDigit(c) || c == '.' && i+1 < len(src) && isDigit(src[i+1]):
+			for i < len(src) && (isDigit(src[i]) || src[i] == '.' ||
+				(src[i] == 'e' || src[i] == 'E') && i+1 < len(src) && isDigit(src[i+1])) {
+				i++
+			}
+			toks = append(toks, token{kind: tokNumber, text: src[start:i], start: start, end: i})
+		case isIdentStart(c):
+			for i < len(src) && (isIdentStart(src[i]) || isDigit(src[i])) {
+				i++
+			}
+			toks = append(toks, token{kind: tokIdent, text: src[start:i], start: start, end: i})
+		case strings.HasPrefix(src[i:], "::"):
+			i += 2
+			toks = append(toks, token{kind: tokPunct, text: "::", start: start, end: i})
+		default:
+			i++
+			toks = append(toks, token{kind: tokPunct, text: string(c), start: start, end: i})
+		}
+	}
+	return toks
+}
+
+// quotedText reads a quoted run starting at s[0]; a doubled closing quote
+// is an escaped quote. Returns the unquoted text and the bytes consumed.
+func quotedText(s string, open, closing byte) (string, int) {
+	var b strings.Builder
+	i := 1
+	for i < len(s) {
+		if s[i] == closing {
+			if i+1 < len(s) && s[i+1] == closing && open == closing {
+				b.WriteByte(closing)
+				i += 2
+				continue
+			}
+			return b.String(), i + 1
+		}
+		b.WriteByte(s[i])
+		i++
+	}
+	return b.String(), i
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isIdentStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '@' || c == '#' || c == '$' || c >= 0x80
+}
+
+// splitStatements splits tokens at ";" and T-SQL "GO" lines, and before a
+// CREATE or ALTER TABLE at the top level (T-SQL scripts often omit both)
+func splitStatements(src string, toks []token) [][]token {
+	var stmts [][]token
+	var cur []token
+	depth := 0
+	flush := func() {
+		if len(cur) > 0 {
+			stmts = append(stmts, cur)
+			cur = nil
+		}
+	}
+	for i, t := range toks {
+		switch {
+		case t.kind == tokPunct && t.text == "(":
+			depth++
+		case t.kind == tokPunct && t.text == ")":
+			depth = max(depth-1, 0)
+		case depth == 0 && t.kind == tokPunct && t.text == ";":
+			flush()
+			continue
+		case depth == 0 && isKeyword(t, "GO") && aloneOnLine(src, t):
+			flush()
+			continue
+		case depth == 0 && len(cur) > 0 && isKeyword(t, "CREATE") && !isKeyword(cur[len(cur)-1], "OR"):
+			flush()
+		case depth == 0 && len(cur) > 0 && isKeyword(t, "ALTER") && i+1 < len(toks) && isKeyword(toks[i+1], "TABLE"):
+			flush()
+		}
+		cur = append(cur, t)
+	}
+	flush()
+	return stmts
+}
+
+// aloneOnLine reports whether t is the only word on its line ("GO", "GO 2")
+func aloneOnLine(src string, t token) bool {
+	lineStart := strings.LastIndexByte(src[:t.start], '\n') + 1
+	lineEnd := strings.IndexByte(src[t.end:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(src) - t.end
+	}
+	before := strings.TrimSpace(src[lineStart:t.start])
+	after := strings.TrimSpace(strings.TrimSuffix(src[t.end:t.end+lineEnd], ";"))
+	return before == "" && strings.Trim(after, "0123456789") == ""
+}
+
+func isKeyword(t token, word string) bool {
+	return t.kind == tokIdent && !t.quoted && strings.EqualFold(t.text, word)
+}
+
+var (
+	mssqlHints  = regexp.MustCompile(`(?im)\[\w+\]|^\s*GO\s*$|\bIDENTITY\s*\(|\bNVARCHAR\b|\bUNIQUEIDENTIFIER\b|\bCLUSTERED\b`)
+	mysqlHints  = regexp.MustCompile("(?i)`|\\bAUTO_INCREMENT\\b|\\bENGINE\\s*=|\\bUNSIGNED\\b")
+	pgHints     = regexp.MustCompile(`(?i)\bSERIAL\b|\bBIGSERIAL\b|::|\bJSONB\b|\bTIMESTAMPTZ\b|\bGENERATED\s+(ALWAYS|BY\s+DEFAULT)\s+AS\s+IDENTITY\b`)
+	sqliteHints = regexp.MustCompile(`(?i)\bAUTOINCREMENT\b|\bWITHOUT\s+ROWID\b`)
+)
+
+// GuessDialect picks the dialect whose syntax a DDL script uses; "" if it
+// uses none of the dialect-specific forms
+func GuessDialect(script string) domain.DBType {
+	switch {
+	case mssqlHints.MatchString(script):
+		return domain.DBTypeMSSQL
+	case mysqlHints.MatchString(script):
+		return domain.DBTypeMySQL
+	case pgHints.MatchString(script):
+		return domain.DBTypePostgres
+	case sqliteHints.MatchString(script):
+		return domain.DBTypeSQLite
+	}
+	return ""
+}
+
+// --- Parser ---
+
+// ddlTable collects a table while the script is parsed
+type ddlTable struct {
+	name        string
+	columns     []rawColumn
+	primaryKey  []string
+	indexes     []domain.SQLIndex
+	foreignKeys []domain.SQLForeignKey
+}
+
+type ddlParser struct {
+	src      string
+	dbType   domain.DBType
+	tables   []*ddlTable
+	byName   map[string]*ddlTable
+	deferred []func() // indexes and constraints added after every table is known
+	warnings []string
+
+	toks []token // current statement
+	pos  int
+}
+
+func (p *ddlParser) warn(t token, format string, args ...any) {
+	line := strings.Count(p.src[:t.start], "\n") + 1
+	p.warnings = append(p.warnings, fmt.Sprintf("line %d: ", line)+fmt.Sprintf(format, args...))
+}
+
+func (p *ddlParser) peek() token {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos]
+	}
+	return token{kind: tokPunct, start: len(p.src), end: len(p.src)}
+}
+
+func (p *ddlParser) done() bool { return p.pos >= len(p.toks) }
+
+func (p *ddlParser) next() token {
+	t := p.peek()
+	if p.pos < len(p.toks) {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the keywords if they come next
+func (p *ddlParser) accept(words ...string) bool {
+	for i, w := range words {
+		if p.pos+i >= len(p.toks) || !isKeyword(p.toks[p.pos+i], w) {
+			return false
+		}
+	}
+	p.pos += len(words)
+	return true
+}
+
+func (p *ddlParser) acceptPunct(s string) bool {
+	if t := p.peek(); t.kind == tokPunct && t.text == s && !p.done() {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// group consumes a parenthesized group and returns the tokens inside
+func (p *ddlParser) group() ([]token, bool) {
+	if !p.acceptPunct("(") {
+		return nil, false
+	}
+	start := p.pos
+	depth := 1
+	for !p.done() {
+		t := p.next()
+		if t.kind != tokPunct {
+			continue
+		}
+		switch t.text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return p.toks[start : p.pos-1], true
+			}
+		}
+	}
+	return p.toks[start:], true
+}
+
+// name reads a possibly qualified name (db.schema.table) and returns its last part
+func (p *ddlParser) name() (string, bool) {
+	t := p.peek()
+	if t.kind != tokIdent {
+		return "", false
+	}
+	p.next()
+	name := t.text
+	for p.acceptPunct(".") {
+		if t := p.peek(); t.kind == tokIdent {
+			name = p.next().text
+		}
+	}
+	return name, true
+}
+
+// raw returns the script text of tokens
+func (p *ddlParser) raw(toks []token) string {
+	if len(toks) == 0 {
+		return ""
+	}
+	return p.src[toks[0].start:toks[len(toks)-1].end]
+}
+
+// ignoredStatements are skipped silently: session settings, data and drops
+var ignoredStatements = map[string]bool{
+	"SET": true, "USE": true, "DROP": true, "BEGIN": true, "COMMIT": true, "END": true, "PRAGMA": true,
+	"INSERT": true, "START": true, "LOCK": true, "UNLOCK": true, "PRINT": true, "GRANT": true, "ROLLBACK": true,
+}
+
+func (p *ddlParser) statement(toks []token) {
+	p.toks, p.pos = toks, 0
+	first := p.peek()
+
+	switch {
+	case p.accept("CREATE"):
+		p.accept("OR", "REPLACE")
+		for p.accept("TEMP") || p.accept("TEMPORARY") || p.accept("GLOBAL") || p.accept("LOCAL") || p.accept("UNLOGGED") {
+		}
+		if p.accept("TABLE") {
+			p.createTable(first)
+			return
+		}
+		unique := p.accept("UNIQUE")
+		p.accept("CLUSTERED")
+		p.accept("NONCLUSTERED")
+		if p.accept("INDEX") {
+			p.createIndex(first, unique)
+			return
+		}
+		p.warn(first, "CREATE %s ignored", strings.ToUpper(p.peek().text))
+	case p.accept("ALTER", "TABLE"):
+		p.alterTable(first)
+	case p.accept("ALTER"):
+		p.warn(first, "ALTER %s ignored", strings.ToUpper(p.peek().text))
+	case first.kind == tokIdent && ignoredStatements[strings.ToUpper(first.text)]:
+	default:
+		p.warn(first, "%s statement ignored", strings.ToUpper(first.text))
+	}
+}
+
+func (p *ddlParser) createTable(first token) {
+	p.accept("IF", "NOT", "EXISTS")
+	name, ok := p.name()
+	if !ok {
+		p.warn(first, "CREATE TABLE without a table name ignored")
+		return
+	}
+	body, ok := p.group()
+	if !ok {
+		p.warn(first, "CREATE TABLE %s without a column list (AS SELECT?) ignored", name)
+		return
+	}
+	if _, dup := p.byName[strings.ToLower(name)]; dup {
+		p.warn(first, "table %s is created twice; the second definition is ignored", name)
+		return
+	}
+
+	t := &ddlTable{name: name}
+	p.tables = append(p.tables, t)
+	p.byName[strings.ToLower(name)] = t
+
+	for _, element := range splitList(body) {
+		sub := &ddlParser{src: p.src, dbType: p.dbType, toks: element}
+		sub.tableElement(t)
+		p.warnings = append(p.warnings, sub.warnings...)
+		p.deferred = append(p.deferred, sub.deferred...)
+	}
+}
+
+// splitList splits the tokens of a group at top-level commas
+func splitList(toks []token) [][]token {
+	var items [][]token
+	depth, start := 0, 0
+	for i, t := range toks {
+		if t.kind != tokPunct {
+			continue
+		}
+		switch t.text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		case ",":
+			if depth == 0 {
+				if i > start {
+					items = append(items, toks[start:i])
+				}
+				start = i + 1
+			}
+		}
+	}
+	if start < len(toks) {
+		items = append(items, toks[start:])
+	}
+	return items
+}
+
+// tableConstraintWords start a table-level constraint instead of a column
+var tableConstraintWords = []string{"CONSTRAINT", "PRIMARY", "UNIQUE", "FOREIGN", "CHECK", "KEY", "INDEX", "FULLTEXT", "SPATIAL", "EXCLUDE", "DEFAULT"}
+
+func (p *ddlParser) tableElement(t *ddlTable) {
+	for _, w := range tableConstraintWords {
+		if isKeyword(p.peek(), w) && !p.columnNamed(w) {
+			p.tableConstraint(t)
+			return
+		}
+	}
+	p.columnDef(t)
+}
+
+// columnNamed tells a column called "key" or "index" (key VARCHAR(10)) from
+// an index (KEY name (col)): an index's parentheses hold column names
+func (p *ddlParser) columnNamed(word string) bool {
+	switch strings.ToUpper(word) {
+	case "KEY", "INDEX", "FULLTEXT", "SPATIAL":
+	default:
+		return false
+	}
+	for i := p.pos + 1; i < len(p.toks) && i <= p.pos+3; i++ {
+		if isPunct(p.toks[i], "(") {
+			return i+1 >= len(p.toks) || p.toks[i+1].kind != tokIdent
+		}
+	}
+	return true
+}
+
+// tableConstraint parses [CONSTRAINT name] PRIMARY KEY | UNIQUE | FOREIGN KEY | KEY/INDEX | CHECK
+func (p *ddlParser) tableConstraint(t *ddlTable) {
+	first := p.peek()
+	var constraint string
+	if p.accept("CONSTRAINT") {
+		constraint, _ = p.name()
+	}
+
+	switch {
+	case p.accept("PRIMARY", "KEY"):
+		p.accept("CLUSTERED")
+		p.accept("NONCLUSTERED")
+		cols, ok := p.columnList(first)
+		if ok {
+			t.primaryKey = cols
+		}
+	case p.accept("UNIQUE"):
+		_ = p.accept("KEY") || p.accept("INDEX")
+		p.accept("CLUSTERED")
+		p.accept("NONCLUSTERED")
+		if p.peek().kind == tokIdent && !p.done() {
+			constraint, _ = p.name()
+		}
+		if cols, ok := p.columnList(first); ok {
+			t.indexes = append(t.indexes, domain.SQLIndex{Name: indexName(constraint, "uq", t.name, cols), Columns: cols, Unique: true})
+		}
+	case p.accept("FOREIGN", "KEY"):
+		if p.peek().kind == tokIdent {
+			p.next() // MySQL index name
+		}
+		cols, ok := p.columnList(first)
+		if !ok {
+			return
+		}
+		p.references(first, t, constraint, cols)
+	case p.accept("KEY") || p.accept("INDEX"):
+		var name string
+		if p.peek().kind == tokIdent {
+			name, _ = p.name()
+		}
+		p.accept("CLUSTERED")
+		p.accept("NONCLUSTERED")
+		if p.accept("USING") {
+			p.next()
+		}
+		if cols, ok := p.columnList(first); ok {
+			t.indexes = append(t.indexes, domain.SQLIndex{Name: indexName(name, "idx", t.name, cols), Columns: cols})
+		}
+	case p.accept("CHECK"):
+		g, _ := p.group()
+		p.warn(first, "%s: CHECK constraint (%s) ignored", t.name, p.raw(g))
+	case p.accept("DEFAULT"):
+		// T-SQL: ADD CONSTRAINT DF_x DEFAULT (value) FOR column
+		expr := p.expression()
+		if !p.accept("FOR") {
+			p.warn(first, "%s: DEFAULT constraint without FOR column ignored", t.name)
+			return
+		}
+		col, _ := p.name()
+		p.deferred = append(p.deferred, func() {
+			for i := range t.columns {
+				if strings.EqualFold(t.columns[i].Name, col) {
+					t.columns[i].Default = sql.NullString{String: expr, Valid: true}
+					return
+				}
+			}
+			p.warn(first, "%s: DEFAULT for unknown column %s ignored", t.name, col)
+		})
+	default:
+		p.warn(first, "%s: constraint %s ignored", t.name, p.raw(p.toks))
+	}
+}
+
+// columnList parses (col [ASC|DESC], ...); expression entries are rejected
+func (p *ddlParser) columnList(first token) ([]string, bool) {
+	g, ok := p.group()
+	if !ok {
+		p.warn(first, "expected a column list in %s", p.raw(p.toks))
+		return nil, false
+	}
+	var cols []string
+	for _, item := range splitList(g) {
+		// MySQL prefix lengths, name(10), are fine; expressions, lower(name), are not
+		prefix := len(item) >= 4 && isPunct(item[1], "(") && item[2].kind == tokNumber && isPunct(item[3], ")")
+		if item[0].kind != tokIdent || len(item) > 1 && item[1].kind == tokPunct && !prefix {
+			p.warn(first, "expression %s in a key or index is not supported", p.raw(item))
+			return nil, false
+		}
+		cols = append(cols, item[0].text)
+	}
+	return cols, len(cols) > 0
+}
+
+// references parses REFERENCES table [(cols)] [ON DELETE action] [ON UPDATE action]
+func (p *ddlParser) references(first token, t *ddlTable, constraint string, cols []string) {
+	if !p.accept("REFERENCES") {
+		p.warn(first, "%s: foreign key without REFERENCES ignored", t.name)
+		return
+	}
+	refTable, _ := p.name()
+	var refCols []string
+	if isPunct(p.peek(), "(") {
+		refCols, _ = p.columnList(first)
+	}
+	onDelete := ""
+	for p.accept("ON") {
+		event := strings.ToUpper(p.next().text)
+		action := strings.ToUpper(p.next().text)
+		if action == "SET" || action == "NO" {
+			action += " " + strings.ToUpper(p.next().text)
+		}
+		if event == "DELETE" {
+			onDelete = action
+		}
+	}
+	p.skipReferenceOptions()
+
+	if len(cols) != 1 || len(refCols) > 1 {
+		p.warn(first, "%s: multi-column foreign key (%s) is not supported", t.name, strings.Join(cols, ", "))
+		return
+	}
+	if onDelete != "" && onDelete != "CASCADE" && onDelete != "NO ACTION" && onDelete != "RESTRICT" {
+		p.warn(first, "%s.%s: ON DELETE %s is not modelled", t.name, cols[0], onDelete)
+	}
+	fk := domain.SQLForeignKey{
+		Name:     constraint,
+		Column:   cols[0],
+		RefTable: refTable,
+		OnDelete: normalizeRule(onDelete),
+	}
+	if fk.Name == "" {
+		fk.Name = "fk_" + t.name + "_" + cols[0]
+	}
+	if len(refCols) == 1 {
+		fk.RefColumn = refCols[0]
+	}
+	t.foreignKeys = append(t.foreignKeys, fk)
+}
+
+// skipReferenceOptions skips MATCH, DEFERRABLE and NOT FOR REPLICATION
+func (p *ddlParser) skipReferenceOptions() {
+	for {
+		switch {
+		case p.accept("MATCH") || p.accept("INITIALLY"):
+			p.next()
+		case p.accept("NOT", "FOR", "REPLICATION") || p.accept("NOT", "DEFERRABLE") || p.accept("DEFERRABLE"):
+		default:
+			return
+		}
+	}
+}
+
+// columnWords end a column's type and start its constraints
+var columnWords = map[string]bool{
+	"NOT": true, "NULL": true, "PRIMARY": true, "UNIQUE": true, "DEFAULT": true, "REFERENCES": true,
+	"CONSTRAINT": true, "CHECK": true, "IDENTITY": true, "AUTO_INCREMENT": true, "AUTOINCREMENT": true,
+	"COLLATE": true, "GENERATED": true, "COMMENT": true, "ON": true, "CHARACTER": true, "CHARSET": true,
+	"AS": true, "SPARSE": true, "ROWGUIDCOL": true, "FILESTREAM": true, "KEY": true,
+}
+
+func (p *ddlParser) columnDef(t *ddlTable) {
+	first := p.peek()
+	name, ok := p.name()
+	if !ok {
+		p.warn(first, "%s: column definition %s ignored", t.name, p.raw(p.toks))
+		return
+	}
+	if p.accept("AS") {
+		p.warn(first, "%s.%s: computed column ignored", t.name, name)
+		return
+	}
+
+	// type words, e.g. "character varying(100)", "bigint unsigned", "[nvarchar](50)"
+	var typeParts []string
+	for !p.done() {
+		tok := p.peek()
+		if tok.kind != tokIdent || len(typeParts) > 0 && (tok.quoted || columnWords[strings.ToUpper(tok.text)]) {
+			break
+		}
+		p.next()
+		part := tok.text
+		for p.acceptPunct(".") && p.peek().kind == tokIdent {
+			part += "." + p.next().text // schema-qualified user type
+		}
+		if isPunct(p.peek(), "(") {
+			g, _ := p.group()
+			part += "(" + p.raw(g) + ")"
+		}
+		if next := p.peek(); next.kind == tokIdent && next.quoted && next.text == "" && p.src[next.start] == '[' {
+			p.next()
+			part += "[]" // PostgreSQL array, lexed as an empty [identifier]
+		}
+		typeParts = append(typeParts, part)
+	}
+	if len(typeParts) == 0 {
+		p.warn(first, "%s.%s: column without a type ignored", t.name, name)
+		return
+	}
+	col := rawColumn{Name: name, Type: strings.Join(typeParts, " ")}
+	if base := strings.ToLower(typeParts[0]); base == "serial" || base == "bigserial" || base == "smallserial" {
+		col.AutoIncrement = true
+	}
+
+	var constraint string
+	for !p.done() {
+		tok := p.peek()
+		switch {
+		case p.accept("NOT", "NULL"):
+			col.NotNull = true
+		case p.accept("NULL"):
+		case p.accept("CONSTRAINT"):
+			constraint, _ = p.name()
+			continue
+		case p.accept("PRIMARY", "KEY"):
+			t.primaryKey = []string{name}
+			for p.accept("ASC") || p.accept("DESC") || p.accept("CLUSTERED") || p.accept("NONCLUSTERED") {
+			}
+			if p.accept("AUTOINCREMENT") {
+				col.AutoIncrement = true
+			}
+		case p.accept("UNIQUE"):
+			_ = p.accept("KEY") || p.accept("CLUSTERED") || p.accept("NONCLUSTERED")
+			t.indexes = append(t.indexes, domain.SQLIndex{Name: indexName(constraint, "uq", t.name, []string{name}), Columns: []string{name}, Unique: true})
+		case p.accept("DEFAULT"):
+			col.Default = sql.NullString{String: p.expression(), Valid: true}
+		case isKeyword(tok, "REFERENCES"):
+			p.references(tok, t, constraint, []string{name})
+		case p.accept("CHECK"):
+			g, _ := p.group()
+			p.warn(tok, "%s.%s: CHECK (%s) ignored", t.name, name, p.raw(g))
+		case p.accept("IDENTITY"):
+			p.group()
+			col.AutoIncrement = true
+		case p.accept("AUTO_INCREMENT") || p.accept("AUTOINCREMENT"):
+			col.AutoIncrement = true
+		case p.accept("GENERATED"):
+			_ = p.accept("ALWAYS") || p.accept("BY", "DEFAULT")
+			p.accept("AS")
+			if p.accept("IDENTITY") {
+				p.group()
+				col.AutoIncrement = true
+			} else {
+				p.group()
+				p.warn(tok, "%s.%s: generated column expression ignored", t.name, name)
+			}
+			_ = p.accept("STORED") || p.accept("VIRTUAL")
+		case p.accept("COLLATE") || p.accept("COMMENT") || p.accept("CHARSET") || p.accept("CHARACTER", "SET"):
+			p.next()
+		case p.accept("ON", "UPDATE"):
+			expr := p.expression()
+			p.warn(tok, "%s.%s: ON UPDATE %s ignored", t.name, name, expr)
+		case p.accept("SPARSE") || p.accept("ROWGUIDCOL") || p.accept("FILESTREAM") || p.accept("ZEROFILL"):
+		default:
+			p.next()
+			p.warn(tok, "%s.%s: %q ignored", t.name, name, tok.text)
+		}
+		constraint = ""
+	}
+	t.columns = append(t.columns, col)
+}
+
+// expression consumes a default expression: a literal, a signed number, a
+// parenthesized group or a function call, with an optional ::cast
+func (p *ddlParser) expression() string {
+	start := p.pos
+	switch tok := p.peek(); {
+	case isPunct(tok, "("):
+		p.group()
+	case isPunct(tok, "-") || isPunct(tok, "+"):
+		p.next()
+		p.next()
+	default:
+		p.next()
+		if tok.kind == tokIdent && isPunct(p.peek(), "(") {
+			p.group()
+		}
+	}
+	for p.acceptPunct("::") {
+		// type words; "character varying" is a type here, not CHARACTER SET
+		for tok := p.peek(); tok.kind == tokIdent && (!columnWords[strings.ToUpper(tok.text)] || isKeyword(tok, "CHARACTER")); tok = p.peek() {
+			p.next()
+		}
+		if isPunct(p.peek(), "(") {
+			p.group()
+		}
+	}
+	return p.raw(p.toks[start:p.pos])
+}
+
+func (p *ddlParser) createIndex(first token, unique bool) {
+	p.accept("CONCURRENTLY")
+	p.accept("IF", "NOT", "EXISTS")
+	name, _ := p.name()
+	if !p.accept("ON") {
+		p.warn(first, "CREATE INDEX %s without ON table ignored", name)
+		return
+	}
+	p.accept("ONLY")
+	table, _ := p.name()
+	if p.accept("USING") {
+		p.next()
+	}
+	cols, ok := p.columnList(first)
+	if !ok {
+		return
+	}
+	for !p.done() {
+		if p.accept("WHERE") {
+			p.warn(first, "partial index %s ignored", name)
+			return
+		}
+		p.next() // INCLUDE (...), WITH (...), ON [PRIMARY]
+	}
+	idx := domain.SQLIndex{Name: indexName(name, "idx", table, cols), Columns: cols, Unique: unique}
+	p.deferred = append(p.deferred, func() {
+		t, ok := p.byName[strings.ToLower(table)]
+		if !ok {
+			p.warn(first, "index %s on unknown table %s ignored", idx.Name, table)
+			return
+		}
+		t.indexes = append(t.indexes, idx)
+	})
+}
+
+// alterTable handles ALTER TABLE t [WITH CHECK] ADD ..., ADD ...
+func (p *ddlParser) alterTable(first token) {
+	p.accept("ONLY")
+	p.accept("IF", "EXISTS")
+	p.accept("ONLY")
+	table, ok := p.name()
+	if !ok {
+		return
+	}
+	_ = p.accept("WITH", "CHECK") || p.accept("WITH", "NOCHECK")
+	if p.accept("CHECK", "CONSTRAINT") || p.accept("NOCHECK", "CONSTRAINT") {
+		return // T-SQL re-enabling a constraint
+	}
+
+	for _, clause := range splitList(p.toks[p.pos:]) {
+		sub := &ddlParser{src: p.src, dbType: p.dbType, toks: clause}
+		if isKeyword(clause[0], "OWNER") || isKeyword(clause[0], "ALTER") && strings.Contains(strings.ToLower(p.raw(clause)), "nextval(") {
+			continue // pg_dump ownership and serial defaults
+		}
+		if !sub.accept("ADD") {
+			p.warn(clause[0], "ALTER TABLE %s %s ignored", table, strings.ToUpper(clause[0].text))
+			continue
+		}
+		sub.accept("COLUMN")
+		clauseToks := sub.toks[sub.pos:]
+		p.deferred = append(p.deferred, func() {
+			t, ok := p.byName[strings.ToLower(table)]
+			if !ok {
+				p.warn(first, "ALTER TABLE on unknown table %s ignored", table)
+				return
+			}
+			add := &ddlParser{src: p.src, dbType: p.dbType, toks: clauseToks}
+			add.tableElement(t)
+			for _, apply := range add.deferred {
+				apply()
+			}
+			p.warnings = append(p.warnings, add.warnings...)
+		})
+	}
+}
+
+func isPunct(t token, s string) bool {
+	return t.kind == tokPunct && t.text == s
+}
+
+// indexName returns name, or a GORM-style name for unnamed indexes
+func indexName(name, prefix, table string, cols []string) string {
+	if name != "" {
+		return name
+	}
+	return prefix + "_" + table + "_" + strings.Join(cols, "_")
+}
+
+// finish converts the collected tables, resolving primary keys and
+// foreign keys that reference a table's primary key implicitly
+func (p *ddlParser) finish() ([]domain.SQLTable, []string) {
+	var tables []domain.SQLTable
+	for _, dt := range p.tables {
+		t := domain.SQLTable{Name: dt.name, Indexes: dt.indexes}
+		single := len(dt.primaryKey) == 1
+		if !single {
+			t.PrimaryKey = dt.primaryKey
+		}
+		for _, rc := range dt.columns {
+			isPK := single && strings.EqualFold(dt.primaryKey[0], rc.Name)
+			if isPK && p.dbType == domain.DBTypeSQLite && strings.EqualFold(rc.Type, "INTEGER") {
+				rc.AutoIncrement = true
+			}
+			c, w := newColumn(p.dbType, dt.name, rc, isPK)
+			t.Columns = append(t.Columns, c)
+			p.warnings = append(p.warnings, w...)
+		}
+		for _, fk := range dt.foreignKeys {
+			if fk.RefColumn == "" {
+				if ref, ok := p.byName[strings.ToLower(fk.RefTable)]; ok && len(ref.primaryKey) == 1 {
+					fk.RefColumn = ref.primaryKey[0]
+				}
+			}
+			if ref, ok := p.byName[strings.ToLower(fk.RefTable)]; ok {
+				fk.RefTable = ref.name
+			}
+			t.ForeignKeys = append(t.ForeignKeys, fk)
+		}
+		tables = append(tables, t)
+	}
+	return tables, p.warnings
+}
@@ -0,0 +1,299 @@
+package introspect
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"ggami-go/internal/domain"
+)
+
+// col is the part of a parsed column the DDL tests compare, written as
+// "name kind(size) pk auto notnull default=x"
+func col(c domain.SQLColumn) string {
+	s := c.Name + " " + c.Kind
+	if c.Size > 0 {
+		s += fmt.Sprintf("(%d)", c.Size)
+	}
+	if c.PrimaryKey {
+		s += " pk"
+	}
+	if c.AutoIncrement {
+		s += " auto"
+	}
+	if c.NotNull {
+		s += " notnull"
+	}
+	if c.Default != "" {
+		s += " default=" + c.Default
+	}
+	return s
+}
+
+// fk writes a foreign key as "column → table.column"
+func fk(f domain.SQLForeignKey) string {
+	s := f.Column + " → " + f.RefTable + "." + f.RefColumn
+	if f.OnDelete != "" {
+		s += " on delete " + f.OnDelete
+	}
+	return s
+}
+
+func TestParseDDL(t *testing.T) {
+	tests := []struct {
+		name     string
+		dbType   domain.DBType
+		script   string
+		columns  map[string][]string // table → col() of each column
+		pk       map[string][]string // table → composite primary key
+		fks      map[string][]string // table → fk() of each foreign key
+		warnings []string            // substrings, one per warning in order
+	}{
+		{
+			name:   "postgres types",
+			dbType: domain.DBTypePostgres,
+			script: `CREATE TABLE items (
+	id bigserial PRIMARY KEY,
+	code varchar(40) NOT NULL,
+	title character varying(20),
+	body text,
+	qty integer DEFAULT 0,
+	big bigint,
+	small smallint,
+	active boolean DEFAULT true,
+	price numeric(10,2),
+	ratio double precision,
+	weight real,
+	seen_at timestamptz,
+	created_at timestamp without time zone,
+	born date,
+	ref uuid,
+	meta jsonb,
+	tags text[]
+);`,
+			columns: map[string][]string{"items": {
+				"id int pk auto notnull",
+				"code string(40) notnull",
+				"title string(20)",
+				"body text",
+				"qty int default=0",
+				"big int",
+				"small int",
+				"active bool default=true",
+				"price decimal",
+				"ratio float64",
+				"weight float64",
+				"seen_at time.Time",
+				"created_at time.Time",
+				"born date",
+				"ref uuid",
+				"meta json",
+				"tags string",
+			}},
+			warnings: []string{"items.tags: no field type for text[]; imported as string"},
+		},
+		{
+			name:   "mysql types",
+			dbType: domain.DBTypeMySQL,
+			script: "CREATE TABLE `items` (\n" +
+				"  `id` int unsigned NOT NULL AUTO_INCREMENT,\n" +
+				"  `flag` tinyint(1) NOT NULL DEFAULT '0',\n" +
+				"  `level` tinyint,\n" +
+				"  `size` enum('s','m','l') DEFAULT 'm',\n" +
+				"  `notes` longtext,\n" +
+				"  `at` datetime,\n" +
+				"  PRIMARY KEY (`id`)\n" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;",
+			columns: map[string][]string{"items": {
+				"id uint pk auto notnull",
+				"flag bool notnull default=0",
+				"level int",
+				"size enum default=m",
+				"notes text",
+				"at time.Time",
+			}},
+		},
+		{
+			name:   "inline primary and foreign keys",
+			dbType: domain.DBTypeSQLite,
+			script: `CREATE TABLE customers (id INTEGER PRIMARY KEY, name TEXT NOT NULL);
+CREATE TABLE orders (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	customer_id INTEGER NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+	parent_id INTEGER REFERENCES orders
+);`,
+			columns: map[string][]string{
+				"customers": {"id int pk auto notnull", "name string notnull"},
+				"orders":    {"id int pk auto notnull", "customer_id int notnull", "parent_id int"},
+			},
+			fks: map[string][]string{"orders": {
+				"customer_id → customers.id on delete CASCADE",
+				"parent_id → orders.id",
+			}},
+		},
+		{
+			name:   "table-level primary and foreign keys",
+			dbType: domain.DBTypePostgres,
+			script: `CREATE TABLE tags (id integer NOT NULL, label text, CONSTRAINT tags_pkey PRIMARY KEY (id));
+CREATE TABLE posts (id integer NOT NULL, PRIMARY KEY (id));
+CREATE TABLE post_tags (
+	post_id integer NOT NULL,
+	tag_id integer NOT NULL,
+	PRIMARY KEY (post_id, tag_id),
+	FOREIGN KEY (post_id) REFERENCES posts (id),
+	CONSTRAINT post_tags_tag_fk FOREIGN KEY (tag_id) REFERENCES tags
+);
+ALTER TABLE ONLY posts ADD COLUMN author_id integer;
+ALTER TABLE ONLY posts ADD CONSTRAINT posts_author_fk FOREIGN KEY (author_id) REFERENCES tags(id);`,
+			columns: map[string][]string{
+				"tags":      {"id int pk notnull", "label text"},
+				"posts":     {"id int pk notnull", "author_id int"},
+				"post_tags": {"post_id int notnull", "tag_id int notnull"},
+			},
+			pk: map[string][]string{"post_tags": {"post_id", "tag_id"}},
+			fks: map[string][]string{
+				"post_tags": {"post_id → posts.id", "tag_id → tags.id"},
+				"posts":     {"author_id → tags.id"},
+			},
+		},
+		{
+			name:   "quoted identifiers",
+			dbType: domain.DBTypeMSSQL,
+			script: `CREATE TABLE [dbo].[Order Items] (
+	[Id] [int] IDENTITY(1,1) NOT NULL,
+	[Item Name] [nvarchar](50) NULL,
+	[Notes] nvarchar(max),
+	[OrderId] int NOT NULL,
+	CONSTRAINT [PK_Order Items] PRIMARY KEY CLUSTERED ([Id] ASC)
+)
+GO
+CREATE TABLE "Orders" ("Id" int NOT NULL PRIMARY KEY, "Select" varchar(10))
+GO
+ALTER TABLE [dbo].[Order Items] ADD CONSTRAINT [FK_Items_Orders] FOREIGN KEY ([OrderId]) REFERENCES [dbo].[Orders] ([Id])
+GO`,
+			columns: map[string][]string{
+				"Order Items": {"Id int pk auto notnull", "Item Name string(50)", "Notes text", "OrderId int notnull"},
+				"Orders":      {"Id int pk notnull", "Select string(10)"},
+			},
+			fks: map[string][]string{"Order Items": {"OrderId → Orders.Id"}},
+		},
+		{
+			name:   "warnings",
+			dbType: domain.DBTypePostgres,
+			script: `CREATE TABLE a (
+	id integer PRIMARY KEY,
+	x integer CHECK (x > 0),
+	y integer GENERATED ALWAYS AS (x * 2) STORED,
+	z geometry,
+	CHECK (x < 100)
+);
+CREATE TABLE b (id integer PRIMARY KEY, a1 integer, a2 integer, FOREIGN KEY (a1, a2) REFERENCES a (id, x));
+CREATE VIEW v AS SELECT * FROM a;
+CREATE INDEX idx_partial ON a (x) WHERE x > 10;
+CREATE INDEX idx_nowhere ON missing (x);
+DROP TABLE old; COMMENT ON TABLE a IS 'drops are skipped quietly';
+CREATE TABLE copy AS SELECT * FROM a;
+CREATE TABLE a (id integer);`,
+			columns: map[string][]string{
+				"a": {"id int pk notnull", "x int", "y int", "z string"},
+				"b": {"id int pk notnull", "a1 int", "a2 int"},
+			},
+			warnings: []string{
+				"line 3: a.x: CHECK (x > 0) ignored",
+				"line 4: a.y: generated column expression ignored",
+				"line 6: a: CHECK constraint (x < 100) ignored",
+				"line 8: b: multi-column foreign key (a1, a2) is not supported",
+				"line 9: CREATE VIEW ignored",
+				"line 10: partial index idx_partial ignored",
+				"line 12: COMMENT statement ignored",
+				"line 13: CREATE TABLE copy without a column list (AS SELECT?) ignored",
+				"line 14: table a is created twice; the second definition is ignored",
+				"line 11: index idx_nowhere on unknown table missing ignored",
+				"a.z: no field type for geometry; imported as string",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tables, warnings := ParseDDL(tt.script, tt.dbType)
+
+			byName := map[string]domain.SQLTable{}
+			for _, table := range tables {
+				byName[table.Name] = table
+			}
+			if len(byName) != len(tt.columns) {
+				var names []string
+				for _, table := range tables {
+					names = append(names, table.Name)
+				}
+				t.Errorf("tables %q, want %d", names, len(tt.columns))
+			}
+			for name, want := range tt.columns {
+				table, ok := byName[name]
+				if !ok {
+					t.Errorf("no table %q", name)
+					continue
+				}
+				var got []string
+				for _, c := range table.Columns {
+					got = append(got, col(c))
+				}
+				if !slices.Equal(got, want) {
+					t.Errorf("%s columns:\n got %q\nwant %q", name, got, want)
+				}
+				if !slices.Equal(table.PrimaryKey, tt.pk[name]) {
+					t.Errorf("%s composite primary key %q, want %q", name, table.PrimaryKey, tt.pk[name])
+				}
+				var fks []string
+				for _, f := range table.ForeignKeys {
+					fks = append(fks, fk(f))
+				}
+				if !slices.Equal(fks, tt.fks[name]) {
+					t.Errorf("%s foreign keys %q, want %q", name, fks, tt.fks[name])
+				}
+			}
+
+			if len(warnings) != len(tt.warnings) {
+				t.Errorf("warnings:\n %s\nwant %d", strings.Join(warnings, "\n "), len(tt.warnings))
+				return
+			}
+			for i, want := range tt.warnings {
+				if !strings.Contains(warnings[i], want) {
+					t.Errorf("warning %d = %q, want %q", i, warnings[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestGuessDialect(t *testing.T) {
+	for script, want := range map[string]domain.DBType{
+		"CREATE TABLE [dbo].[x] ([id] int)\nGO":                  domain.DBTypeMSSQL,
+		"CREATE TABLE `x` (`id` int) ENGINE=InnoDB;":             domain.DBTypeMySQL,
+		"CREATE TABLE public.x (id serial PRIMARY KEY);":         domain.DBTypePostgres,
+		"CREATE TABLE x (id INTEGER PRIMARY KEY AUTOINCREMENT);": domain.DBTypeSQLite,
+	} {
+		if got := GuessDialect(script); got != want {
+			t.Errorf("GuessDialect(%q) = %q, want %q", script, got, want)
+		}
+	}
+}
+
+func TestImportDDL(t *testing.T) {
+	result := ImportDDL(`CREATE TABLE customers (id INTEGER PRIMARY KEY, name VARCHAR(80) NOT NULL);
+CREATE TABLE orders (id INTEGER PRIMARY KEY, customer_id INTEGER NOT NULL REFERENCES customers(id));
+CREATE TRIGGER t AFTER INSERT ON orders BEGIN SELECT 1; END;`, domain.DBTypeSQLite)
+
+	if len(result.Models) != 2 || result.Models[0].Name != "Customer" || result.Models[1].Name != "Order" {
+		t.Fatalf("models %+v, want Customer and Order", result.Models)
+	}
+	want := []domain.RelationDef{{Name: "Customer", Type: domain.RelationBelongsTo, Model: "Customer"}}
+	if got := result.Models[1].Relations; !slices.Equal(got, want) {
+		t.Errorf("Order relations %+v, want %+v", got, want)
+	}
+	if len(result.Warnings) == 0 || !strings.Contains(result.Warnings[0], "CREATE TRIGGER ignored") {
+		t.Errorf("warnings %q, want the trigger skipped", result.Warnings)
+	}
+}
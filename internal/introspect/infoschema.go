@@ -0,0 +1,187 @@
+package introspect
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"ggami-go/internal/domain"
+)
+
+// infoSchemaDialect holds what differs between the INFORMATION_SCHEMA
+// databases. Queries use {schema} for the current schema and {p} for the
+// table name parameter.
+type infoSchemaDialect struct {
+	schema      string // expression for the current schema
+	param       string // placeholder of the first parameter
+	typeExpr    string // column type; lengths are appended unless it already has them
+	sized       bool   // typeExpr already includes the length
+	autoIncExpr string // 1/true when the column is generated by the database
+	fkQuery     string
+	indexQuery  string
+}
+
+var infoSchemaDialects = map[domain.DBType]infoSchemaDialect{
+	domain.DBTypePostgres: {
+		schema:      "current_schema()",
+		param:       "$1",
+		typeExpr:    "data_type",
+		autoIncExpr: "(column_default LIKE 'nextval(%' OR is_identity = 'YES')",
+		fkQuery: `SELECT rc.constraint_name, kcu.column_name, ccu.table_name, ccu.column_name, rc.delete_rule
+FROM information_schema.referential_constraints rc
+JOIN information_schema.key_column_usage kcu
+  ON kcu.constraint_schema = rc.constraint_schema AND kcu.constraint_name = rc.constraint_name
+JOIN information_schema.key_column_usage ccu
+  ON ccu.constraint_schema = rc.unique_constraint_schema AND ccu.constraint_name = rc.unique_constraint_name
+  AND ccu.ordinal_position = kcu.position_in_unique_constraint
+WHERE kcu.table_schema = {schema} AND kcu.table_name = {p}
+ORDER BY rc.constraint_name, kcu.ordinal_position`,
+		indexQuery: `SELECT i.relname, a.attname, ix.indisunique
+FROM pg_index ix
+JOIN pg_class t ON t.oid = ix.indrelid
+JOIN pg_class i ON i.oid = ix.indexrelid
+JOIN pg_namespace n ON n.oid = t.relnamespace
+JOIN LATERAL unnest(ix.indkey) WITH ORDINALITY AS k(attnum, ord) ON true
+JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+WHERE n.nspname = {schema} AND t.relname = {p} AND NOT ix.indisprimary
+ORDER BY i.relname, k.ord`,
+	},
+	domain.DBTypeMySQL: {
+		schema:      "DATABASE()",
+		param:       "?",
+		typeExpr:    "COLUMN_TYPE",
+		sized:       true,
+		autoIncExpr: "(EXTRA LIKE '%auto_increment%')",
+		fkQuery: `SELECT kcu.CONSTRAINT_NAME, kcu.COLUMN_NAME, kcu.REFERENCED_TABLE_NAME, kcu.REFERENCED_COLUMN_NAME, rc.DELETE_RULE
+FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE kcu
+JOIN INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS rc
+  ON rc.CONSTRAINT_SCHEMA = kcu.CONSTRAINT_SCHEMA AND rc.CONSTRAINT_NAME = kcu.CONSTRAINT_NAME
+WHERE kcu.TABLE_SCHEMA = {schema} AND kcu.TABLE_NAME = {p} AND kcu.REFERENCED_TABLE_NAME IS NOT NULL
+ORDER BY kcu.CONSTRAINT_NAME, kcu.ORDINAL_POSITION`,
+		indexQuery: `SELECT INDEX_NAME, COLUMN_NAME, NON_UNIQUE = 0
+FROM INFORMATION_SCHEMA.STATISTICS
+WHERE TABLE_SCHEMA = {schema} AND TABLE_NAME = {p} AND INDEX_NAME <> 'PRIMARY'
+ORDER BY INDEX_NAME, SEQ_IN_INDEX`,
+	},
+	domain.DBTypeMSSQL: {
+		schema:      "SCHEMA_NAME()",
+		param:       "@p1",
+		typeExpr:    "DATA_TYPE",
+		autoIncExpr: "CASE WHEN COLUMNPROPERTY(OBJECT_ID(QUOTENAME(TABLE_SCHEMA) + '.' + QUOTENAME(TABLE_NAME)), COLUMN_NAME, 'IsIdentity') = 1 THEN 1 ELSE 0 END",
+		// SQL Server's KEY_COLUMN_USAGE has no POSITION_IN_UNIQUE_CONSTRAINT
+		fkQuery: `SELECT rc.CONSTRAINT_NAME, kcu.COLUMN_NAME, ccu.TABLE_NAME, ccu.COLUMN_NAME, rc.DELETE_RULE
+FROM INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS rc
+JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE kcu
+  ON kcu.CONSTRAINT_SCHEMA = rc.CONSTRAINT_SCHEMA AND kcu.CONSTRAINT_NAME = rc.CONSTRAINT_NAME
+JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE ccu
+  ON ccu.CONSTRAINT_SCHEMA = rc.UNIQUE_CONSTRAINT_SCHEMA AND ccu.CONSTRAINT_NAME = rc.UNIQUE_CONSTRAINT_NAME
+  AND ccu.ORDINAL_POSITION = kcu.ORDINAL_POSITION
+WHERE kcu.TABLE_SCHEMA = {schema} AND kcu.TABLE_NAME = {p}
+ORDER BY rc.CONSTRAINT_NAME, kcu.ORDINAL_POSITION`,
+		indexQuery: `SELECT i.name, c.name, i.is_unique
+FROM sys.indexes i
+JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+WHERE i.object_id = OBJECT_ID(QUOTENAME({schema}) + '.' + QUOTENAME({p}))
+  AND i.is_primary_key = 0 AND i.name IS NOT NULL AND ic.is_included_column = 0
+ORDER BY i.name, ic.key_ordinal`,
+	},
+}
+
+// infoSchemaReader reads PostgreSQL, MySQL and SQL Server catalogs
+type infoSchemaReader struct {
+	db      *sql.DB
+	dialect infoSchemaDialect
+}
+
+func newInfoSchemaReader(db *sql.DB, dbType domain.DBType) infoSchemaReader {
+	return infoSchemaReader{db: db, dialect: infoSchemaDialects[dbType]}
+}
+
+// query expands {schema} and {p} and runs q with the table name
+func (r infoSchemaReader) query(ctx context.Context, q string, args ...any) (*sql.Rows, error) {
+	q = strings.NewReplacer("{schema}", r.dialect.schema, "{p}", r.dialect.param).Replace(q)
+	return r.db.QueryContext(ctx, q, args...)
+}
+
+func (r infoSchemaReader) tableNames(ctx context.Context) ([]string, error) {
+	rows, err := r.query(ctx, `SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES
+WHERE TABLE_SCHEMA = {schema} AND TABLE_TYPE = 'BASE TABLE' AND TABLE_NAME <> 'schema_migrations'
+ORDER BY TABLE_NAME`)
+	if err != nil {
+		return nil, err
+	}
+	return scanStrings(rows)
+}
+
+func (r infoSchemaReader) columns(ctx context.Context, table string) ([]rawColumn, error) {
+	rows, err := r.query(ctx, fmt.Sprintf(`SELECT COLUMN_NAME, %s, CHARACTER_MAXIMUM_LENGTH, IS_NULLABLE, COLUMN_DEFAULT, %s
+FROM INFORMATION_SCHEMA.COLUMNS
+WHERE TABLE_SCHEMA = {schema} AND TABLE_NAME = {p}
+ORDER BY ORDINAL_POSITION`, r.dialect.typeExpr, r.dialect.autoIncExpr), table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []rawColumn
+	for rows.Next() {
+		var c rawColumn
+		var length sql.NullInt64
+		var nullable string
+		if err := rows.Scan(&c.Name, &c.Type, &length, &nullable, &c.Default, &c.AutoIncrement); err != nil {
+			return nil, err
+		}
+		c.NotNull = strings.EqualFold(nullable, "NO")
+		if !r.dialect.sized && length.Valid {
+			switch {
+			case length.Int64 < 0:
+				c.Type += "(max)"
+			case length.Int64 > 0:
+				c.Type += fmt.Sprintf("(%d)", length.Int64)
+			}
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+func (r infoSchemaReader) primaryKey(ctx context.Context, table string) ([]string, error) {
+	rows, err := r.query(ctx, `SELECT kcu.COLUMN_NAME
+FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc
+JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE kcu
+  ON kcu.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA AND kcu.CONSTRAINT_NAME = tc.CONSTRAINT_NAME
+  AND kcu.TABLE_NAME = tc.TABLE_NAME
+WHERE tc.CONSTRAINT_TYPE = 'PRIMARY KEY' AND tc.TABLE_SCHEMA = {schema} AND tc.TABLE_NAME = {p}
+ORDER BY kcu.ORDINAL_POSITION`, table)
+	if err != nil {
+		return nil, err
+	}
+	return scanStrings(rows)
+}
+
+func (r infoSchemaReader) indexes(ctx context.Context, table string) ([]domain.SQLIndex, error) {
+	rows, err := r.query(ctx, r.dialect.indexQuery, table)
+	if err != nil {
+		return nil, err
+	}
+	return groupIndexes(rows)
+}
+
+func (r infoSchemaReader) foreignKeys(ctx context.Context, table string) ([]rawForeignKey, error) {
+	rows, err := r.query(ctx, r.dialect.fkQuery, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []rawForeignKey
+	for rows.Next() {
+		var fk rawForeignKey
+		if err := rows.Scan(&fk.Name, &fk.Column, &fk.RefTable, &fk.RefColumn, &fk.OnDelete); err != nil {
+			return nil, err
+		}
+		out = append(out, fk)
+	}
+	return out, rows.Err()
+}
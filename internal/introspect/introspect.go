@@ -0,0 +1,289 @@
+// Package introspect reads the schema of an existing database (tables,
+// columns, keys, indexes and foreign keys), or of the DDL script that
+// created it, and maps it to generator models.
+package introspect
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "github.com/microsoft/go-mssqldb"
+	_ "modernc.org/sqlite"
+
+	"ggami-go/internal/domain"
+)
+
+// driverNames maps DBType to the database/sql driver used for reading
+var driverNames = map[domain.DBType]string{
+	domain.DBTypeSQLite:   "sqlite",
+	domain.DBTypePostgres: "pgx",
+	domain.DBTypeMySQL:    "mysql",
+	domain.DBTypeMSSQL:    "sqlserver",
+}
+
+// reader reads one database's catalog
+type reader interface {
+	tableNames(ctx context.Context) ([]string, error)
+	columns(ctx context.Context, table string) ([]rawColumn, error)
+	primaryKey(ctx context.Context, table string) ([]string, error)
+	indexes(ctx context.Context, table string) ([]domain.SQLIndex, error)
+	foreignKeys(ctx context.Context, table string) ([]rawForeignKey, error)
+}
+
+// rawColumn is a column as the catalog reports it
+type rawColumn struct {
+	Name          string
+	Type          string // declared type, e.g. "varchar(100)", "bigint unsigned"
+	NotNull       bool
+	Default       sql.NullString // default expression as stored in the catalog
+	AutoIncrement bool
+}
+
+// rawForeignKey is one column of a foreign key constraint
+type rawForeignKey struct {
+	Name      string
+	Column    string
+	RefTable  string
+	RefColumn string
+	OnDelete  string
+}
+
+// DSN builds a connection string from the connection fields of a config.
+// For SQLite, DBName is the database file. sslMode sets the PostgreSQL
+// sslmode; when empty the driver's default (prefer) applies.
+func DSN(config domain.ProjectConfig, sslMode string) (string, error) {
+	user := url.UserPassword(config.DBUser, config.DBPw)
+	switch config.DBType {
+	case domain.DBTypeSQLite, "":
+		if config.DBName == "" {
+			return "", fmt.Errorf("dbName must be the SQLite database file")
+		}
+		return config.DBName, nil
+	case domain.DBTypeMSSQL:
+		u := url.URL{Scheme: "sqlserver", User: user, Host: config.DBServer, RawQuery: url.Values{"database": {config.DBName}}.Encode()}
+		return u.String(), nil
+	case domain.DBTypePostgres:
+		u := url.URL{Scheme: "postgres", User: user, Host: config.DBServer, Path: "/" + config.DBName}
+		if sslMode != "" {
+			u.RawQuery = url.Values{"sslmode": {sslMode}}.Encode()
+		}
+		return u.String(), nil
+	case domain.DBTypeMySQL:
+		return fmt.Sprintf("%s:%s@tcp(%s)/%s", config.DBUser, config.DBPw, config.DBServer, config.DBName), nil
+	default:
+		return "", fmt.Errorf("unsupported database type %q", config.DBType)
+	}
+}
+
+// Open connects to a database for reading its schema. SQLite files are
+// opened read-only and must exist.
+func Open(dbType domain.DBType, dsn string) (*sql.DB, error) {
+	if dbType == "" {
+		dbType = domain.DBTypeSQLite
+	}
+	driver, ok := driverNames[dbType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type %q", dbType)
+	}
+	if dbType == domain.DBTypeSQLite && !strings.HasPrefix(dsn, "file:") {
+		if _, err := os.Stat(dsn); err != nil {
+			return nil, fmt.Errorf("sqlite database: %w", err)
+		}
+		dsn = "file:" + dsn + "?mode=ro"
+	}
+	return sql.Open(driver, dsn)
+}
+
+// ReadSchema reads every user table of the database. Constraints that do
+// not fit domain.SQLTable (multi-column foreign keys) are reported as warnings.
+func ReadSchema(ctx context.Context, db *sql.DB, dbType domain.DBType) ([]domain.SQLTable, []string, error) {
+	r, err := newReader(db, dbType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names, err := r.tableNames(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list tables: %w", err)
+	}
+
+	var tables []domain.SQLTable
+	var warnings []string
+	for _, name := range names {
+		t, w, err := readTable(ctx, r, dbType, name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("table %s: %w", name, err)
+		}
+		tables = append(tables, t)
+		warnings = append(warnings, w...)
+	}
+	return tables, warnings, nil
+}
+
+// Import connects to a database and maps its schema to models
+func Import(ctx context.Context, dbType domain.DBType, dsn string) (*domain.ModelImport, error) {
+	db, err := Open(dbType, dsn)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+
+	tables, warnings, err := ReadSchema(ctx, db, dbType)
+	if err != nil {
+		return nil, err
+	}
+	result := ToModels(tables)
+	result.Warnings = append(warnings, result.Warnings...)
+	return &result, nil
+}
+
+func newReader(db *sql.DB, dbType domain.DBType) (reader, error) {
+	switch dbType {
+	case domain.DBTypeSQLite, "":
+		return sqliteReader{db}, nil
+	case domain.DBTypePostgres, domain.DBTypeMySQL, domain.DBTypeMSSQL:
+		return newInfoSchemaReader(db, dbType), nil
+	default:
+		return nil, fmt.Errorf("unsupported database type %q", dbType)
+	}
+}
+
+// readTable assembles one table from the catalog
+func readTable(ctx context.Context, r reader, dbType domain.DBType, name string) (domain.SQLTable, []string, error) {
+	t := domain.SQLTable{Name: name}
+	var warnings []string
+
+	cols, err := r.columns(ctx, name)
+	if err != nil {
+		return t, nil, fmt.Errorf("columns: %w", err)
+	}
+	pk, err := r.primaryKey(ctx, name)
+	if err != nil {
+		return t, nil, fmt.Errorf("primary key: %w", err)
+	}
+	if len(pk) > 1 {
+		t.PrimaryKey = pk
+	}
+
+	for _, rc := range cols {
+		c, w := newColumn(dbType, name, rc, len(pk) == 1 && pk[0] == rc.Name)
+		t.Columns = append(t.Columns, c)
+		warnings = append(warnings, w...)
+	}
+
+	if t.Indexes, err = r.indexes(ctx, name); err != nil {
+		return t, nil, fmt.Errorf("indexes: %w", err)
+	}
+
+	fks, err := r.foreignKeys(ctx, name)
+	if err != nil {
+		return t, nil, fmt.Errorf("foreign keys: %w", err)
+	}
+	columnsOf := make(map[string]int)
+	for _, fk := range fks {
+		columnsOf[fk.Name]++
+	}
+	warned := make(map[string]bool)
+	for _, fk := range fks {
+		if columnsOf[fk.Name] > 1 {
+			if !warned[fk.Name] {
+				warnings = append(warnings, fmt.Sprintf("%s: multi-column foreign key %s is not supported", name, fk.Name))
+				warned[fk.Name] = true
+			}
+			continue
+		}
+		t.ForeignKeys = append(t.ForeignKeys, domain.SQLForeignKey{
+			Name:      fk.Name,
+			Column:    fk.Column,
+			RefTable:  fk.RefTable,
+			RefColumn: fk.RefColumn,
+			OnDelete:  normalizeRule(fk.OnDelete),
+		})
+	}
+	return t, warnings, nil
+}
+
+// newColumn classifies a catalog column
+func newColumn(dbType domain.DBType, table string, rc rawColumn, primaryKey bool) (domain.SQLColumn, []string) {
+	var warnings []string
+	kind, size := classify(dbType, rc.Type)
+	c := domain.SQLColumn{
+		Name:          rc.Name,
+		Kind:          kind,
+		Size:          size,
+		RawType:       rc.Type,
+		PrimaryKey:    primaryKey,
+		AutoIncrement: rc.AutoIncrement,
+		NotNull:       rc.NotNull || primaryKey,
+	}
+	if kind == "enum" {
+		c.EnumValues = enumValues(rc.Type)
+	}
+	if rc.Default.Valid {
+		value, ok := defaultValue(dbType, rc.Default.String)
+		switch {
+		case ok:
+			c.Default = value
+		case !c.AutoIncrement:
+			warnings = append(warnings, fmt.Sprintf("%s.%s: default %s is an expression and was not imported", table, rc.Name, rc.Default.String))
+		}
+	}
+	if kind == "" {
+		c.Kind = "string"
+		warnings = append(warnings, fmt.Sprintf("%s.%s: no field type for %s; imported as string", table, rc.Name, rc.Type))
+	}
+	return c, warnings
+}
+
+// normalizeRule keeps the delete rules the generator emits
+func normalizeRule(rule string) string {
+	if strings.EqualFold(rule, "CASCADE") {
+		return "CASCADE"
+	}
+	return ""
+}
+
+// groupIndexes folds (index, column, unique) rows into indexes, keeping order
+func groupIndexes(rows *sql.Rows) ([]domain.SQLIndex, error) {
+	defer rows.Close()
+	var out []domain.SQLIndex
+	pos := make(map[string]int)
+	for rows.Next() {
+		var name, column string
+		var unique bool
+		if err := rows.Scan(&name, &column, &unique); err != nil {
+			return nil, err
+		}
+		i, ok := pos[name]
+		if !ok {
+			i = len(out)
+			pos[name] = i
+			out = append(out, domain.SQLIndex{Name: name, Unique: unique})
+		}
+		out[i].Columns = append(out[i].Columns, column)
+	}
+	return out, rows.Err()
+}
+
+// scanStrings reads a single string column
+func scanStrings(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+	var out []string
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,256 @@
+package introspect
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"ggami-go/internal/domain"
+	"ggami-go/internal/generator"
+)
+
+// sqliteSchema covers the column types, keys and constraints the SQLite
+// reader maps
+const sqliteSchema = `
+CREATE TABLE customers (
+	id INTEGER PRIMARY KEY,
+	full_name VARCHAR(100) NOT NULL,
+	email TEXT,
+	active BOOLEAN NOT NULL DEFAULT 1,
+	credit DECIMAL(10,2) DEFAULT 0,
+	joined_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_customers_email ON customers(email);
+CREATE TABLE orders (
+	id INTEGER PRIMARY KEY,
+	customer_id INTEGER NOT NULL REFERENCES customers(id),
+	total REAL,
+	note TEXT DEFAULT 'none',
+	placed_on DATE
+);
+CREATE TABLE tags (
+	id INTEGER PRIMARY KEY,
+	label VARCHAR(50) NOT NULL
+);
+CREATE TABLE order_tags (
+	order_id INTEGER NOT NULL REFERENCES orders(id),
+	tag_id INTEGER NOT NULL REFERENCES tags,
+	PRIMARY KEY (order_id, tag_id)
+);
+CREATE TABLE schema_migrations (version INTEGER PRIMARY KEY);
+`
+
+// sqliteFile creates a SQLite database file holding schema
+func sqliteFile(t *testing.T, schema string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "legacy.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	for _, stmt := range strings.Split(schema, ";") {
+		if strings.TrimSpace(stmt) == "" {
+			continue
+		}
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	return path
+}
+
+func tableNamed(t *testing.T, tables []domain.SQLTable, name string) domain.SQLTable {
+	t.Helper()
+	for _, table := range tables {
+		if table.Name == name {
+			return table
+		}
+	}
+	t.Fatalf("no table %s", name)
+	return domain.SQLTable{}
+}
+
+func columnNamed(t *testing.T, table domain.SQLTable, name string) domain.SQLColumn {
+	t.Helper()
+	for _, c := range table.Columns {
+		if c.Name == name {
+			return c
+		}
+	}
+	t.Fatalf("no column %s.%s", table.Name, name)
+	return domain.SQLColumn{}
+}
+
+func TestReadSchemaSQLite(t *testing.T) {
+	db, err := Open(domain.DBTypeSQLite, sqliteFile(t, sqliteSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	tables, warnings, err := ReadSchema(context.Background(), db, domain.DBTypeSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"customers.joined_at: default CURRENT_TIMESTAMP is an expression and was not imported"}; !slices.Equal(warnings, want) {
+		t.Errorf("warnings %q, want %q", warnings, want)
+	}
+
+	var names []string
+	for _, table := range tables {
+		names = append(names, table.Name)
+	}
+	if want := []string{"customers", "order_tags", "orders", "tags"}; !slices.Equal(names, want) {
+		t.Errorf("tables %q, want %q (schema_migrations left out)", names, want)
+	}
+
+	customers := tableNamed(t, tables, "customers")
+	for _, want := range []domain.SQLColumn{
+		{Name: "id", Kind: "int", RawType: "INTEGER", PrimaryKey: true, AutoIncrement: true, NotNull: true},
+		{Name: "full_name", Kind: "string", Size: 100, RawType: "VARCHAR(100)", NotNull: true},
+		{Name: "email", Kind: "string", RawType: "TEXT"},
+		{Name: "active", Kind: "bool", RawType: "BOOLEAN", NotNull: true, Default: "1"},
+		{Name: "credit", Kind: "decimal", RawType: "DECIMAL(10,2)", Default: "0"},
+		{Name: "joined_at", Kind: "time.Time", RawType: "DATETIME"},
+	} {
+		got := columnNamed(t, customers, want.Name)
+		if got.Kind != want.Kind || got.Size != want.Size || got.RawType != want.RawType || got.PrimaryKey != want.PrimaryKey ||
+			got.AutoIncrement != want.AutoIncrement || got.NotNull != want.NotNull || got.Default != want.Default {
+			t.Errorf("customers.%s = %+v, want %+v", want.Name, got, want)
+		}
+	}
+	if len(customers.Indexes) != 1 || customers.Indexes[0].Name != "idx_customers_email" || !customers.Indexes[0].Unique ||
+		!slices.Equal(customers.Indexes[0].Columns, []string{"email"}) {
+		t.Errorf("customers indexes %+v, want the unique idx_customers_email on email", customers.Indexes)
+	}
+
+	orders := tableNamed(t, tables, "orders")
+	if c := columnNamed(t, orders, "customer_id"); !c.NotNull || c.Kind != "int" {
+		t.Errorf("orders.customer_id = %+v, want a NOT NULL int", c)
+	}
+	if c := columnNamed(t, orders, "total"); c.Kind != "float64" || c.NotNull {
+		t.Errorf("orders.total = %+v, want a nullable float64", c)
+	}
+	if c := columnNamed(t, orders, "note"); c.Default != "none" {
+		t.Errorf("orders.note default %q, want none", c.Default)
+	}
+	if c := columnNamed(t, orders, "placed_on"); c.Kind != "date" {
+		t.Errorf("orders.placed_on kind %q, want date", c.Kind)
+	}
+	if len(orders.ForeignKeys) != 1 || orders.ForeignKeys[0].Column != "customer_id" ||
+		orders.ForeignKeys[0].RefTable != "customers" || orders.ForeignKeys[0].RefColumn != "id" {
+		t.Errorf("orders foreign keys %+v, want customer_id → customers.id", orders.ForeignKeys)
+	}
+
+	joins := tableNamed(t, tables, "order_tags")
+	if !slices.Equal(joins.PrimaryKey, []string{"order_id", "tag_id"}) {
+		t.Errorf("order_tags primary key %q, want order_id, tag_id", joins.PrimaryKey)
+	}
+	refs := map[string]string{}
+	for _, fk := range joins.ForeignKeys {
+		refs[fk.Column] = fk.RefTable + "." + fk.RefColumn
+	}
+	// tag_id names no column: the parent's primary key is meant
+	if refs["order_id"] != "orders.id" || refs["tag_id"] != "tags.id" {
+		t.Errorf("order_tags foreign keys %v", refs)
+	}
+}
+
+func TestImportSQLite(t *testing.T) {
+	result, err := Import(context.Background(), domain.DBTypeSQLite, sqliteFile(t, sqliteSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range result.Warnings {
+		if !strings.Contains(w, "joined_at: default CURRENT_TIMESTAMP") {
+			t.Errorf("unexpected warning %q", w)
+		}
+	}
+
+	models := map[string]domain.ModelDef{}
+	var names []string
+	for _, m := range result.Models {
+		models[m.Name] = m
+		names = append(names, m.Name)
+	}
+	if want := []string{"Customer", "Order", "Tag"}; !slices.Equal(names, want) {
+		t.Fatalf("models %q, want %q", names, want)
+	}
+
+	field := func(model, name string) domain.FieldDef {
+		t.Helper()
+		for _, f := range models[model].Fields {
+			if f.Name == name {
+				return f
+			}
+		}
+		t.Fatalf("no field %s.%s", model, name)
+		return domain.FieldDef{}
+	}
+	for _, c := range []struct {
+		model, field, typ, jsonName, defaultVal string
+		tags                                    []string
+	}{
+		{"Customer", "ID", "uint", "id", "", []string{"primaryKey"}},
+		{"Customer", "FullName", "string", "full_name", "", []string{"not null", "size:100"}},
+		{"Customer", "Email", "string", "email", "", []string{"uniqueIndex"}},
+		{"Customer", "Active", "bool", "active", "true", []string{"not null"}},
+		{"Customer", "Credit", "decimal", "credit", "0", nil},
+		{"Order", "CustomerID", "uint", "customer_id", "", []string{"not null"}},
+		{"Order", "Total", "float64", "total", "", nil},
+		{"Order", "Note", "string", "note", "none", nil},
+		{"Tag", "Label", "string", "label", "", []string{"not null", "size:50"}},
+	} {
+		f := field(c.model, c.field)
+		if f.Type != c.typ || f.JsonName != c.jsonName || f.DefaultVal != c.defaultVal || !slices.Equal(f.GormTags, c.tags) {
+			t.Errorf("%s.%s = %+v, want type %s, json %s, default %q, tags %q", c.model, c.field, f, c.typ, c.jsonName, c.defaultVal, c.tags)
+		}
+	}
+
+	// order_tags only links orders and tags, so it becomes a relation, not a model
+	want := []domain.RelationDef{
+		{Name: "Customer", Type: domain.RelationBelongsTo, Model: "Customer"},
+		{Name: "Tags", Type: domain.RelationMany2Many, Model: "Tag", JoinTable: "order_tags"},
+	}
+	if rels := models["Order"].Relations; !slices.Equal(rels, want) {
+		t.Errorf("Order relations %+v, want %+v", rels, want)
+	}
+	if rels := append(models["Customer"].Relations, models["Tag"].Relations...); len(rels) != 0 {
+		t.Errorf("Customer and Tag relations %+v, want none", rels)
+	}
+
+	// the config generates the tables it was read from
+	config := domain.ProjectConfig{ProjectName: "legacy", GormMode: true, DBType: domain.DBTypeSQLite, Models: result.Models}
+	generated := generator.BuildSQLSchema(config)
+	var generatedNames []string
+	for _, table := range generated {
+		generatedNames = append(generatedNames, table.Name)
+	}
+	slices.Sort(generatedNames)
+	if want := []string{"customers", "order_tags", "orders", "tags"}; !slices.Equal(generatedNames, want) {
+		t.Errorf("generated tables %q, want %q", generatedNames, want)
+	}
+	orders := tableNamed(t, generated, "orders")
+	if c := columnNamed(t, orders, "customer_id"); !c.NotNull {
+		t.Errorf("generated orders.customer_id is nullable")
+	}
+	if len(orders.ForeignKeys) != 1 || orders.ForeignKeys[0].RefTable != "customers" {
+		t.Errorf("generated orders foreign keys %+v, want customer_id → customers", orders.ForeignKeys)
+	}
+}
+
+func TestDSN(t *testing.T) {
+	config := domain.ProjectConfig{DBType: domain.DBTypePostgres, DBServer: "db:5432", DBUser: "app", DBPw: "p@ss", DBName: "shop"}
+	for sslMode, want := range map[string]string{
+		"":        "postgres://app:p%40ss@db:5432/shop",
+		"require": "postgres://app:p%40ss@db:5432/shop?sslmode=require",
+	} {
+		got, err := DSN(config, sslMode)
+		if err != nil || got != want {
+			t.Errorf("DSN(sslmode %q) = %q, %v; want %q", sslMode, got, err, want)
+		}
+	}
+}
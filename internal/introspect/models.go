@@ -0,0 +1,392 @@
+package introspect
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/jinzhu/inflection"
+	"gorm.io/gorm/schema"
+
+	"ggami-go/internal/domain"
+)
+
+// gormNaming is the naming GORM applies to models and fields at runtime;
+// names that do not round-trip get explicit table and column overrides
+var gormNaming = schema.NamingStrategy{}
+
+// initialisms are kept upper case in field names, as golint and GORM expect
+var initialisms = map[string]bool{
+	"API": true, "CPU": true, "CSS": true, "DNS": true, "HTML": true, "HTTP": true, "HTTPS": true,
+	"ID": true, "IP": true, "JSON": true, "SKU": true, "SQL": true, "UI": true, "UID": true,
+	"URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// importedModel tracks the model built for a table
+type importedModel struct {
+	def   domain.ModelDef
+	table domain.SQLTable
+	idCol string         // primary key column
+	idInt bool           // integer primary key (required for relations)
+	field map[string]int // column → index in def.Fields
+}
+
+// ToModels maps tables to models. Every table with a single-column primary
+// key becomes a model; two-column tables that only link two models become
+// many2many relations; single-column foreign keys become belongsTo
+// relations. Anything that cannot be expressed is reported in Warnings.
+func ToModels(tables []domain.SQLTable) domain.ModelImport {
+	var result domain.ModelImport
+	warn := func(format string, args ...any) {
+		result.Warnings = append(result.Warnings, fmt.Sprintf(format, args...))
+	}
+
+	var models []*importedModel
+	byTable := make(map[string]*importedModel)
+	byName := make(map[string]*importedModel)
+	var joins []domain.SQLTable
+
+	for _, t := range tables {
+		pk := primaryKeyOf(t)
+		if len(pk) != 1 {
+			if len(t.Columns) == 2 && len(t.ForeignKeys) == 2 {
+				joins = append(joins, t)
+				continue
+			}
+			warn("%s: skipped, models need a single-column primary key", t.Name)
+			continue
+		}
+
+		name := modelName(t.Name)
+		if other, ok := byName[name]; ok {
+			warn("%s: skipped, model name %s is already used by table %s", t.Name, name, other.table.Name)
+			continue
+		}
+		m := newModel(name, t, pk[0], warn)
+		models = append(models, m)
+		byTable[t.Name] = m
+		byName[name] = m
+	}
+
+	// belongsTo edges added so far, to keep the graph acyclic
+	parents := make(map[string][]string)
+	for _, m := range models {
+		for _, fk := range m.table.ForeignKeys {
+			addBelongsTo(m, fk, byTable, parents, warn)
+		}
+	}
+	for _, t := range joins {
+		addMany2Many(t, byTable, warn)
+	}
+
+	for _, m := range models {
+		result.Models = append(result.Models, m.def)
+	}
+	return result
+}
+
+// newModel builds the fields of a table's model
+func newModel(name string, t domain.SQLTable, pkCol string, warn func(string, ...any)) *importedModel {
+	m := &importedModel{
+		def:   domain.ModelDef{Name: name},
+		table: t,
+		idCol: pkCol,
+		field: make(map[string]int),
+	}
+	if gormNaming.TableName(name) != t.Name {
+		m.def.TableName = t.Name
+	}
+
+	single := make(map[string]domain.SQLIndex)
+	for _, idx := range t.Indexes {
+		if len(idx.Columns) == 1 {
+			if prev, ok := single[idx.Columns[0]]; !ok || idx.Unique && !prev.Unique {
+				single[idx.Columns[0]] = idx
+			}
+			continue
+		}
+		warn("%s: composite index %s (%s) was not imported", t.Name, idx.Name, strings.Join(idx.Columns, ", "))
+	}
+
+	used := map[string]bool{"ID": true}
+	for _, c := range t.Columns {
+		if c.Name == pkCol {
+			m.field[c.Name] = len(m.def.Fields)
+			m.def.Fields = append(m.def.Fields, idField(t.Name, c, warn))
+			m.idInt = c.Kind == "int" || c.Kind == "uint"
+			continue
+		}
+
+		fieldName := fieldName(c.Name)
+		if used[fieldName] {
+			fieldName = uniqueName(fieldName, used)
+		}
+		used[fieldName] = true
+
+		f := domain.FieldDef{Name: fieldName, Type: c.Kind, JsonName: c.Name, DefaultVal: c.Default, EnumValues: c.EnumValues}
+		if gormNaming.ColumnName("", fieldName) != c.Name {
+			f.GormTags = append(f.GormTags, "column:"+c.Name)
+		}
+		if c.NotNull {
+			f.GormTags = append(f.GormTags, "not null")
+		}
+		if c.Kind == "string" && c.Size > 0 && c.Size != 255 {
+			f.GormTags = append(f.GormTags, "size:"+strconv.Itoa(c.Size))
+		}
+		if c.Kind == "bool" {
+			f.DefaultVal = boolDefault(c.Default)
+		}
+		if idx, ok := single[c.Name]; ok {
+			if idx.Unique {
+				f.GormTags = append(f.GormTags, "uniqueIndex")
+			} else {
+				f.GormTags = append(f.GormTags, "index")
+			}
+		}
+		m.field[c.Name] = len(m.def.Fields)
+		m.def.Fields = append(m.def.Fields, f)
+	}
+	return m
+}
+
+// idField maps the primary key column to the ID field
+func idField(table string, c domain.SQLColumn, warn func(string, ...any)) domain.FieldDef {
+	f := domain.FieldDef{Name: "ID", Type: c.Kind, GormTags: []string{"primaryKey"}, JsonName: c.Name}
+	switch c.Kind {
+	case "int", "uint":
+		f.Type = "uint"
+	default:
+		warn("%s: primary key %s is %s; relations to this table are not imported", table, c.Name, c.Kind)
+	}
+	if c.Name != "id" {
+		f.GormTags = append(f.GormTags, "column:"+c.Name)
+	}
+	return f
+}
+
+// addBelongsTo turns a foreign key into a belongsTo relation on m
+func addBelongsTo(m *importedModel, fk domain.SQLForeignKey, byTable map[string]*importedModel, parents map[string][]string, warn func(string, ...any)) {
+	where := m.table.Name + "." + fk.Column
+	target, ok := byTable[fk.RefTable]
+	switch {
+	case !ok:
+		warn("%s: references %s, which was not imported", where, fk.RefTable)
+		return
+	case fk.RefColumn != target.idCol:
+		warn("%s: references %s.%s, which is not its primary key", where, fk.RefTable, fk.RefColumn)
+		return
+	case !target.idInt:
+		warn("%s: references %s, whose primary key is not an integer", where, fk.RefTable)
+		return
+	case target == m:
+		warn("%s: self reference kept as a plain field (belongsTo cannot point at its own model)", where)
+		return
+	case reaches(parents, target.def.Name, m.def.Name):
+		warn("%s: kept as a plain field, a belongsTo relation would form a cycle", where)
+		return
+	}
+
+	i, ok := m.field[fk.Column]
+	if !ok {
+		return
+	}
+	field := &m.def.Fields[i]
+	if field.Type != "int" && field.Type != "uint" {
+		warn("%s: foreign key is %s, not an integer; kept as a plain field", where, field.Type)
+		return
+	}
+	field.Type = "uint"
+
+	name := strings.TrimSuffix(field.Name, "ID")
+	if name == "" || name == field.Name {
+		name = target.def.Name
+	}
+	if m.hasName(name) {
+		warn("%s: relation %s clashes with a field or relation; kept as a plain field", where, name)
+		return
+	}
+
+	rel := domain.RelationDef{Name: name, Type: domain.RelationBelongsTo, Model: target.def.Name}
+	if field.Name != name+"ID" {
+		rel.ForeignKey = field.Name
+	}
+	if fk.OnDelete == "CASCADE" {
+		warn("%s: ON DELETE CASCADE is not modelled by belongsTo", where)
+	}
+	m.def.Relations = append(m.def.Relations, rel)
+	parents[m.def.Name] = append(parents[m.def.Name], target.def.Name)
+}
+
+// addMany2Many turns a join table into a many2many relation on the model of
+// its first column. Column names must be GORM's defaults for the pair.
+func addMany2Many(t domain.SQLTable, byTable map[string]*importedModel, warn func(string, ...any)) {
+	refs := make(map[string]domain.SQLForeignKey)
+	for _, fk := range t.ForeignKeys {
+		refs[fk.Column] = fk
+	}
+	first, ok1 := refs[t.Columns[0].Name]
+	second, ok2 := refs[t.Columns[1].Name]
+	if !ok1 || !ok2 {
+		warn("%s: skipped, models need a single-column primary key", t.Name)
+		return
+	}
+
+	owner, okOwner := byTable[first.RefTable]
+	target, okTarget := byTable[second.RefTable]
+	switch {
+	case !okOwner || !okTarget:
+		warn("%s: join table references a table that was not imported", t.Name)
+		return
+	case owner == target:
+		warn("%s: self-referencing join table was not imported", t.Name)
+		return
+	case !owner.idInt || !target.idInt || first.RefColumn != owner.idCol || second.RefColumn != target.idCol:
+		warn("%s: join table must reference integer primary keys", t.Name)
+		return
+	case first.Column != gormNaming.ColumnName("", owner.def.Name+"ID") || second.Column != gormNaming.ColumnName("", target.def.Name+"ID"):
+		warn("%s: join table columns must be %s and %s to map to many2many", t.Name,
+			gormNaming.ColumnName("", owner.def.Name+"ID"), gormNaming.ColumnName("", target.def.Name+"ID"))
+		return
+	}
+
+	name := inflection.Plural(target.def.Name)
+	if owner.hasName(name) {
+		warn("%s: relation %s.%s clashes with a field or relation", t.Name, owner.def.Name, name)
+		return
+	}
+	owner.def.Relations = append(owner.def.Relations, domain.RelationDef{
+		Name:      name,
+		Type:      domain.RelationMany2Many,
+		Model:     target.def.Name,
+		JoinTable: t.Name,
+	})
+}
+
+func (m *importedModel) hasName(name string) bool {
+	for _, f := range m.def.Fields {
+		if f.Name == name {
+			return true
+		}
+	}
+	for _, r := range m.def.Relations {
+		if r.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// reaches reports whether to is reachable from from over belongsTo edges
+func reaches(parents map[string][]string, from, to string) bool {
+	seen := make(map[string]bool)
+	var visit func(string) bool
+	visit = func(n string) bool {
+		if n == to {
+			return true
+		}
+		if seen[n] {
+			return false
+		}
+		seen[n] = true
+		for _, p := range parents[n] {
+			if visit(p) {
+				return true
+			}
+		}
+		return false
+	}
+	return visit(from)
+}
+
+func primaryKeyOf(t domain.SQLTable) []string {
+	if len(t.PrimaryKey) > 0 {
+		return t.PrimaryKey
+	}
+	var pk []string
+	for _, c := range t.Columns {
+		if c.PrimaryKey {
+			pk = append(pk, c.Name)
+		}
+	}
+	return pk
+}
+
+// modelName derives a singular PascalCase model name from a table name
+func modelName(table string) string {
+	words := splitWords(table)
+	if len(words) > 0 {
+		last := len(words) - 1
+		words[last] = inflection.Singular(words[last])
+	}
+	return pascal(words)
+}
+
+// fieldName derives a PascalCase field name from a column name
+func fieldName(column string) string {
+	return pascal(splitWords(column))
+}
+
+// splitWords splits snake_case, kebab-case and camelCase names into words
+func splitWords(s string) []string {
+	var words []string
+	var cur []rune
+	flush := func() {
+		if len(cur) > 0 {
+			words = append(words, strings.ToLower(string(cur)))
+			cur = nil
+		}
+	}
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) ||
+			i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1])):
+			flush()
+			cur = append(cur, r)
+		default:
+			cur = append(cur, r)
+		}
+	}
+	flush()
+	return words
+}
+
+// pascal joins words into an exported Go identifier
+func pascal(words []string) string {
+	var b strings.Builder
+	for _, w := range words {
+		if up := strings.ToUpper(w); initialisms[up] {
+			b.WriteString(up)
+			continue
+		}
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	name := b.String()
+	if name == "" || !unicode.IsLetter(rune(name[0])) {
+		name = "X" + name
+	}
+	return name
+}
+
+func uniqueName(name string, used map[string]bool) string {
+	for i := 2; ; i++ {
+		candidate := name + strconv.Itoa(i)
+		if !used[candidate] {
+			return candidate
+		}
+	}
+}
+
+// boolDefault normalises 0/1 defaults of bit and tinyint columns
+func boolDefault(v string) string {
+	switch v {
+	case "1":
+		return "true"
+	case "0":
+		return "false"
+	}
+	return v
+}
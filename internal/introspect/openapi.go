@@ -0,0 +1,630 @@
+package introspect
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jinzhu/inflection"
+	"gopkg.in/yaml.v3"
+
+	"ggami-go/internal/domain"
+)
+
+// schemaObject is the part of a JSON Schema / OpenAPI Schema Object the
+// importer reads
+type schemaObject struct {
+	Ref        string          `yaml:"$ref"`
+	Title      string          `yaml:"title"`
+	Type       typeList        `yaml:"type"`
+	Format     string          `yaml:"format"`
+	Enum       []any           `yaml:"enum"`
+	Required   []string        `yaml:"required"`
+	Properties namedSchemas    `yaml:"properties"`
+	Items      *schemaObject   `yaml:"items"`
+	AllOf      []*schemaObject `yaml:"allOf"`
+	OneOf      []*schemaObject `yaml:"oneOf"`
+	AnyOf      []*schemaObject `yaml:"anyOf"`
+	Nullable   bool            `yaml:"nullable"` // OpenAPI 3.0; 3.1 uses type: [T, "null"]
+	Default    any             `yaml:"default"`
+	MinLength  *int            `yaml:"minLength"`
+	MaxLength  *int            `yaml:"maxLength"`
+	Minimum    *float64        `yaml:"minimum"`
+	Maximum    *float64        `yaml:"maximum"`
+	Pattern    string          `yaml:"pattern"`
+}
+
+// typeList accepts both "type": "string" and "type": ["string", "null"]
+type typeList []string
+
+func (t *typeList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*t = typeList{node.Value}
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*t = list
+	return nil
+}
+
+// namedSchema is one entry of a schema map, kept in document order
+type namedSchema struct {
+	Name   string
+	Schema *schemaObject
+}
+
+// namedSchemas decodes a name → schema mapping without losing its order
+type namedSchemas []namedSchema
+
+func (n *namedSchemas) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: expected a mapping of schemas", node.Line)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		s := new(schemaObject)
+		if err := node.Content[i+1].Decode(s); err != nil {
+			return err
+		}
+		*n = append(*n, namedSchema{Name: node.Content[i].Value, Schema: s})
+	}
+	return nil
+}
+
+// apiDocument is an OpenAPI 3 (or Swagger 2) document or a JSON Schema file
+type apiDocument struct {
+	Components struct {
+		Schemas namedSchemas `yaml:"schemas"`
+	} `yaml:"components"`
+	Defs         namedSchemas `yaml:"$defs"`
+	Definitions  namedSchemas `yaml:"definitions"` // Swagger 2 and JSON Schema draft-07
+	schemaObject `yaml:",inline"`
+}
+
+// apiModel tracks the model built for a schema
+type apiModel struct {
+	def   domain.ModelDef
+	idInt bool
+	refs  []apiRef
+	used  map[string]bool // field and relation names
+}
+
+// apiRef is a property that points at another model's schema
+type apiRef struct {
+	prop   string
+	target *apiModel
+	many   bool
+}
+
+// apiImporter maps the schemas of one document to models
+type apiImporter struct {
+	schemas  map[string]*schemaObject // by $ref
+	models   map[string]*apiModel     // by $ref
+	order    []*apiModel
+	warnings []string
+}
+
+// ImportOpenAPI maps the object schemas of an OpenAPI 3 document
+// (components.schemas) or a JSON Schema file ($defs, definitions or the root
+// schema) to models. The document may be YAML or JSON; rootName names a root
+// schema that has no title. Properties holding a $ref to another object
+// schema become belongsTo relations, arrays of them hasMany or many2many.
+func ImportOpenAPI(document []byte, rootName string) (*domain.ModelImport, error) {
+	var doc apiDocument
+	if err := yaml.Unmarshal(document, &doc); err != nil {
+		return nil, fmt.Errorf("parse document: %w", err)
+	}
+
+	imp := &apiImporter{schemas: make(map[string]*schemaObject), models: make(map[string]*apiModel)}
+	var named []namedSchema // keyed by $ref
+	for _, group := range []struct {
+		prefix  string
+		schemas namedSchemas
+	}{
+		{"#/components/schemas/", doc.Components.Schemas},
+		{"#/$defs/", doc.Defs},
+		{"#/definitions/", doc.Definitions},
+	} {
+		for _, s := range group.schemas {
+			ref := group.prefix + escapePointer(s.Name)
+			imp.schemas[ref] = s.Schema
+			named = append(named, namedSchema{Name: ref, Schema: s.Schema})
+		}
+	}
+	if root := &doc.schemaObject; len(root.Properties) > 0 || len(root.AllOf) > 0 {
+		imp.schemas["#"] = root
+		named = append(named, namedSchema{Name: "#", Schema: root})
+	}
+	if len(named) == 0 {
+		return nil, fmt.Errorf("no schemas found in components.schemas, $defs, definitions or the root schema")
+	}
+
+	// models first, so properties can tell relations from plain fields
+	byName := make(map[string]string)
+	for _, s := range named {
+		props, _ := imp.flatten(s.Schema, nil)
+		if len(props) == 0 {
+			continue // enums and other value schemas are inlined where referenced
+		}
+		name := schemaName(s.Name, s.Schema, rootName)
+		if other, ok := byName[name]; ok {
+			imp.warn("%s: skipped, model name %s is already used by %s", s.Name, name, other)
+			continue
+		}
+		byName[name] = s.Name
+		m := &apiModel{def: domain.ModelDef{Name: name}, used: map[string]bool{"ID": true}}
+		imp.models[s.Name] = m
+		imp.order = append(imp.order, m)
+	}
+	for _, s := range named {
+		if m, ok := imp.models[s.Name]; ok {
+			imp.addFields(m, s.Schema)
+		}
+	}
+
+	// belongsTo edges added so far, to keep the graph acyclic
+	parents := make(map[string][]string)
+	for _, m := range imp.order {
+		for _, ref := range m.refs {
+			if !ref.many {
+				imp.addBelongsTo(m, ref, parents)
+			}
+		}
+	}
+	for _, m := range imp.order {
+		for _, ref := range m.refs {
+			if ref.many {
+				imp.addToMany(m, ref)
+			}
+		}
+	}
+
+	result := &domain.ModelImport{Warnings: imp.warnings}
+	for _, m := range imp.order {
+		result.Models = append(result.Models, m.def)
+	}
+	return result, nil
+}
+
+func (imp *apiImporter) warn(format string, args ...any) {
+	imp.warnings = append(imp.warnings, fmt.Sprintf(format, args...))
+}
+
+// flatten collects the properties of a schema, merging allOf parts and the
+// schemas they reference, and the names of its required properties
+func (imp *apiImporter) flatten(s *schemaObject, seen map[*schemaObject]bool) (namedSchemas, map[string]bool) {
+	required := make(map[string]bool)
+	if s == nil || seen[s] {
+		return nil, required
+	}
+	if seen == nil {
+		seen = make(map[*schemaObject]bool)
+	}
+	seen[s] = true
+
+	var props namedSchemas
+	index := make(map[string]int)
+	merge := func(more namedSchemas, req map[string]bool) {
+		for _, p := range more {
+			if i, ok := index[p.Name]; ok {
+				props[i] = p // later parts override, as in allOf inheritance
+				continue
+			}
+			index[p.Name] = len(props)
+			props = append(props, p)
+		}
+		for name := range req {
+			required[name] = true
+		}
+	}
+
+	if s.Ref != "" {
+		merge(imp.flatten(imp.schemas[s.Ref], seen))
+	}
+	for _, part := range s.AllOf {
+		merge(imp.flatten(part, seen))
+	}
+	merge(s.Properties, nil)
+	for _, name := range s.Required {
+		required[name] = true
+	}
+	return props, required
+}
+
+// addFields maps the properties of a model's schema to fields and records
+// the ones that reference other models
+func (imp *apiImporter) addFields(m *apiModel, s *schemaObject) {
+	props, required := imp.flatten(s, nil)
+	for _, p := range props {
+		where := m.def.Name + "." + p.Name
+		prop, nullable := imp.unwrap(where, p.Schema)
+
+		if prop.Ref != "" {
+			if target, ok := imp.models[prop.Ref]; ok {
+				m.refs = append(m.refs, apiRef{prop: p.Name, target: target})
+				continue
+			}
+		}
+		if prop.Type.has("array") && prop.Items != nil {
+			if item, _ := imp.unwrap(where, prop.Items); item.Ref != "" {
+				if target, ok := imp.models[item.Ref]; ok {
+					m.refs = append(m.refs, apiRef{prop: p.Name, target: target, many: true})
+					continue
+				}
+			}
+		}
+
+		name := fieldName(p.Name)
+		if name == "ID" {
+			m.def.Fields = append([]domain.FieldDef{imp.idField(m, where, p.Name, prop)}, m.def.Fields...)
+			continue
+		}
+		if m.used[name] {
+			name = uniqueName(name, m.used)
+		}
+		m.used[name] = true
+
+		f := imp.field(where, name, p.Name, prop)
+		if required[p.Name] && !nullable {
+			f.GormTags = append(f.GormTags, "not null")
+			if f.Type != "bool" {
+				// a required checkbox could never be left unchecked
+				f.Validation = withRequired(f.Validation)
+			}
+		}
+		m.def.Fields = append(m.def.Fields, f)
+	}
+
+	if len(m.def.Fields) == 0 || m.def.Fields[0].Name != "ID" {
+		id := domain.FieldDef{Name: "ID", Type: "uint", GormTags: []string{"primaryKey"}, JsonName: "id"}
+		m.def.Fields = append([]domain.FieldDef{id}, m.def.Fields...)
+		m.idInt = true
+	}
+}
+
+// idField maps an "id" property to the primary key
+func (imp *apiImporter) idField(m *apiModel, where, prop string, s *schemaObject) domain.FieldDef {
+	f := domain.FieldDef{Name: "ID", Type: "uint", GormTags: []string{"primaryKey"}, JsonName: prop}
+	if t := imp.fieldType(where, s); t != "int" && t != "uint" {
+		f.Type = t
+		imp.warn("%s: primary key is %s; relations to this schema are not imported", where, t)
+		return f
+	}
+	m.idInt = true
+	return f
+}
+
+// field maps one property to a field with its validation rules
+func (imp *apiImporter) field(where, name, prop string, s *schemaObject) domain.FieldDef {
+	f := domain.FieldDef{Name: name, Type: imp.fieldType(where, s), JsonName: prop}
+	if f.Type == "enum" {
+		f.EnumValues = enumStrings(s.Enum)
+	}
+	if d, ok := scalarDefault(s.Default); ok {
+		f.DefaultVal = d
+	}
+	if f.Type == "string" && s.MaxLength != nil && *s.MaxLength != 255 {
+		f.GormTags = append(f.GormTags, fmt.Sprintf("size:%d", *s.MaxLength))
+	}
+
+	v := &domain.FieldValidation{}
+	switch f.Type {
+	case "int", "uint", "float64", "decimal":
+		v.Min, v.Max = s.Minimum, s.Maximum
+	case "string", "text":
+		if s.MinLength != nil && *s.MinLength > 0 {
+			v.MinLength = *s.MinLength
+		}
+		if s.MaxLength != nil && *s.MaxLength > 0 {
+			v.MaxLength = *s.MaxLength
+		}
+		if s.Pattern != "" {
+			if _, err := regexp.Compile(s.Pattern); err != nil {
+				imp.warn("%s: pattern %s is not a Go regular expression and was not imported", where, s.Pattern)
+			} else {
+				v.Pattern = s.Pattern
+			}
+		}
+		v.Email = s.Format == "email"
+	}
+	if *v != (domain.FieldValidation{}) {
+		f.Validation = v
+	}
+	return f
+}
+
+// maxStringSize is the longest maxLength kept as a sized string column
+const maxStringSize = 4000
+
+// fieldType picks the field type registry name for a property schema
+func (imp *apiImporter) fieldType(where string, s *schemaObject) string {
+	types := s.Type.withoutNull()
+	if len(types) > 1 {
+		imp.warn("%s: union type %s imported as json", where, strings.Join(types, " | "))
+		return "json"
+	}
+	if len(s.OneOf) > 0 || len(s.AnyOf) > 0 {
+		imp.warn("%s: oneOf/anyOf imported as json", where)
+		return "json"
+	}
+	kind := ""
+	if len(types) == 1 {
+		kind = types[0]
+	}
+	if len(s.Enum) > 0 {
+		values := enumStrings(s.Enum)
+		if len(values) > 0 && (kind == "string" || kind == "" && len(values) == len(nonNull(s.Enum))) {
+			if err := checkEnumValues(values); err != nil {
+				imp.warn("%s: enum imported as string, %v", where, err)
+				return "string"
+			}
+			return "enum"
+		}
+		imp.warn("%s: enum values of a non-string property are not enforced", where)
+	}
+
+	switch kind {
+	case "string":
+		switch s.Format {
+		case "date-time":
+			return "time.Time"
+		case "date":
+			return "date"
+		case "uuid":
+			return "uuid"
+		case "binary":
+			return "file"
+		case "byte":
+			return "text"
+		}
+		if s.MaxLength != nil && *s.MaxLength > maxStringSize {
+			return "text"
+		}
+		return "string"
+	case "integer":
+		return "int"
+	case "number":
+		if s.Format == "decimal" {
+			return "decimal"
+		}
+		return "float64"
+	case "boolean":
+		return "bool"
+	}
+	return "json" // objects, arrays of values and untyped schemas
+}
+
+// unwrap follows $refs to value schemas and single-$ref compositions such
+// as allOf: [{$ref}] or anyOf: [{$ref}, {type: null}]. References to model
+// schemas are kept so the caller can turn them into relations.
+func (imp *apiImporter) unwrap(where string, s *schemaObject) (*schemaObject, bool) {
+	nullable := false
+	for depth := 0; s != nil && depth < 32; depth++ {
+		nullable = nullable || s.Nullable || s.Type.has("null")
+
+		parts := s.AllOf
+		if len(parts) == 0 {
+			parts = s.AnyOf
+		}
+		if len(parts) == 0 {
+			parts = s.OneOf
+		}
+		var refs []*schemaObject
+		nullPart := false
+		for _, p := range parts {
+			if p.Ref == "" && p.Type.isNull() {
+				nullPart = true
+				continue
+			}
+			refs = append(refs, p)
+		}
+		if len(s.Properties) == 0 && len(refs) == 1 && refs[0].Ref != "" {
+			nullable = nullable || nullPart
+			s = refs[0]
+			continue
+		}
+
+		if s.Ref == "" {
+			return s, nullable
+		}
+		if _, ok := imp.models[s.Ref]; ok {
+			return s, nullable
+		}
+		target, ok := imp.schemas[s.Ref]
+		if !ok {
+			imp.warn("%s: unresolved reference %s imported as json", where, s.Ref)
+			return &schemaObject{}, nullable
+		}
+		s = target
+	}
+	if s == nil {
+		return &schemaObject{}, nullable
+	}
+	return s, nullable
+}
+
+// addBelongsTo turns a $ref property into a belongsTo relation on m
+func (imp *apiImporter) addBelongsTo(m *apiModel, ref apiRef, parents map[string][]string) {
+	where := m.def.Name + "." + ref.prop
+	target := ref.target
+	name := fieldName(ref.prop)
+	fk := name + "ID"
+	switch {
+	case !target.idInt:
+		imp.warn("%s: references %s, whose primary key is not an integer", where, target.def.Name)
+		return
+	case target == m:
+		imp.warn("%s: self reference was not imported (belongsTo cannot point at its own model)", where)
+		return
+	case reaches(parents, target.def.Name, m.def.Name):
+		imp.warn("%s: not imported, a belongsTo relation would form a cycle", where)
+		return
+	case m.used[name]:
+		imp.warn("%s: relation %s clashes with a field; not imported", where, name)
+		return
+	}
+
+	for i := range m.def.Fields {
+		f := &m.def.Fields[i]
+		if f.Name != fk {
+			continue
+		}
+		if f.Type != "int" && f.Type != "uint" {
+			imp.warn("%s: foreign key %s is %s, not an integer; relation not imported", where, fk, f.Type)
+			return
+		}
+		f.Type = "uint"
+		f.Validation = nil // checked through the relation select
+	}
+
+	m.used[name] = true
+	m.def.Relations = append(m.def.Relations, domain.RelationDef{Name: name, Type: domain.RelationBelongsTo, Model: target.def.Name})
+	parents[m.def.Name] = append(parents[m.def.Name], target.def.Name)
+}
+
+// addToMany turns an array of $refs into hasMany when the target belongs to
+// m, and into many2many otherwise
+func (imp *apiImporter) addToMany(m *apiModel, ref apiRef) {
+	where := m.def.Name + "." + ref.prop
+	target := ref.target
+	name := fieldName(ref.prop)
+	switch {
+	case !m.idInt || !target.idInt:
+		imp.warn("%s: to-many relations need integer primary keys; not imported", where)
+		return
+	case target == m:
+		imp.warn("%s: self-referencing array was not imported", where)
+		return
+	case m.used[name]:
+		imp.warn("%s: relation %s clashes with a field; not imported", where, name)
+		return
+	}
+
+	rel := domain.RelationDef{Name: name, Type: domain.RelationMany2Many, Model: target.def.Name}
+	for _, back := range target.def.Relations {
+		if back.Model != m.def.Name {
+			continue
+		}
+		switch back.Type {
+		case domain.RelationBelongsTo:
+			rel.Type = domain.RelationHasMany
+			if fk := back.ResolvedForeignKey(target.def.Name); fk != m.def.Name+"ID" {
+				rel.ForeignKey = fk
+			}
+		case domain.RelationMany2Many:
+			imp.warn("%s: reverse side of many2many %s.%s was not imported", where, target.def.Name, back.Name)
+			return
+		}
+	}
+	m.used[name] = true
+	m.def.Relations = append(m.def.Relations, rel)
+}
+
+// schemaName derives the model name from a schema's key, or from the title
+// of a root schema
+func schemaName(ref string, s *schemaObject, rootName string) string {
+	if ref == "#" {
+		switch {
+		case s.Title != "":
+			return pascal(splitWords(s.Title))
+		case rootName != "":
+			return pascal(splitWords(rootName))
+		}
+		return "Model"
+	}
+	key := ref[strings.LastIndex(ref, "/")+1:]
+	key = strings.NewReplacer("~1", "/", "~0", "~").Replace(key)
+	words := splitWords(key)
+	if len(words) > 0 {
+		last := len(words) - 1
+		words[last] = inflection.Singular(words[last])
+	}
+	return pascal(words)
+}
+
+// escapePointer escapes a schema key for use in a JSON pointer $ref
+func escapePointer(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
+
+func withRequired(v *domain.FieldValidation) *domain.FieldValidation {
+	if v == nil {
+		v = &domain.FieldValidation{}
+	}
+	v.Required = true
+	return v
+}
+
+func (t typeList) has(name string) bool {
+	for _, s := range t {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (t typeList) isNull() bool {
+	return len(t) == 1 && t[0] == "null"
+}
+
+func (t typeList) withoutNull() []string {
+	var out []string
+	for _, s := range t {
+		if s != "null" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func nonNull(values []any) []any {
+	var out []any
+	for _, v := range values {
+		if v != nil {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// enumStrings returns the string values of an enum, skipping null
+func enumStrings(values []any) []string {
+	var out []string
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// checkEnumValues applies the generator's limits on enum values
+func checkEnumValues(values []string) error {
+	seen := make(map[string]bool)
+	for _, v := range values {
+		switch {
+		case strings.TrimSpace(v) == "":
+			return fmt.Errorf("empty values are not allowed")
+		case len(v) > 64:
+			return fmt.Errorf("value %q is longer than 64 characters", v)
+		case seen[v]:
+			return fmt.Errorf("duplicate value %q", v)
+		}
+		seen[v] = true
+	}
+	return nil
+}
+
+// scalarDefault formats a scalar default value as DefaultVal
+func scalarDefault(v any) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case bool, int, int64, uint64, float64:
+		return fmt.Sprint(v), true
+	}
+	return "", false
+}
@@ -0,0 +1,164 @@
+package introspect
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"ggami-go/internal/domain"
+)
+
+func importFixture(t *testing.T, name, rootName string) *domain.ModelImport {
+	t.Helper()
+	document, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := ImportOpenAPI(document, rootName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func float(v float64) *float64 { return &v }
+
+// checkModels compares imported models field by field, so a failure names
+// the field that differs
+func checkModels(t *testing.T, got, want []domain.ModelDef) {
+	t.Helper()
+	var gotNames, wantNames []string
+	for _, m := range got {
+		gotNames = append(gotNames, m.Name)
+	}
+	for _, m := range want {
+		wantNames = append(wantNames, m.Name)
+	}
+	if !slices.Equal(gotNames, wantNames) {
+		t.Fatalf("models %q, want %q", gotNames, wantNames)
+	}
+	for i, m := range want {
+		g := got[i]
+		for j, f := range m.Fields {
+			if j >= len(g.Fields) {
+				t.Errorf("%s: no field %s", m.Name, f.Name)
+				continue
+			}
+			if !reflect.DeepEqual(g.Fields[j], f) {
+				t.Errorf("%s field %d:\n got %+v %+v\nwant %+v %+v", m.Name, j, g.Fields[j], g.Fields[j].Validation, f, f.Validation)
+			}
+		}
+		if len(g.Fields) > len(m.Fields) {
+			t.Errorf("%s: unexpected fields %+v", m.Name, g.Fields[len(m.Fields):])
+		}
+		if !slices.Equal(g.Relations, m.Relations) {
+			t.Errorf("%s relations %+v, want %+v", m.Name, g.Relations, m.Relations)
+		}
+	}
+}
+
+func TestImportOpenAPI(t *testing.T) {
+	result := importFixture(t, "petstore.yaml", "")
+
+	id := domain.FieldDef{Name: "ID", Type: "uint", GormTags: []string{"primaryKey"}, JsonName: "id"}
+	checkModels(t, result.Models, []domain.ModelDef{
+		{Name: "Category", Fields: []domain.FieldDef{
+			id,
+			{Name: "Name", Type: "string", JsonName: "name", GormTags: []string{"size:50", "not null"},
+				Validation: &domain.FieldValidation{Required: true, MaxLength: 50}},
+		}},
+		{Name: "Tag", Fields: []domain.FieldDef{
+			id,
+			{Name: "Label", Type: "string", JsonName: "label"},
+		}},
+		{Name: "Owner", Fields: []domain.FieldDef{
+			id,
+			{Name: "Email", Type: "string", JsonName: "email", GormTags: []string{"not null"},
+				Validation: &domain.FieldValidation{Required: true, Email: true}},
+		}, Relations: []domain.RelationDef{
+			// Pet.owner points back, so the array is the other side of a belongsTo
+			{Name: "Pets", Type: domain.RelationHasMany, Model: "Pet"},
+		}},
+		{Name: "Pet", Fields: []domain.FieldDef{
+			id,
+			{Name: "Name", Type: "string", JsonName: "name", GormTags: []string{"size:80", "not null"},
+				Validation: &domain.FieldValidation{Required: true, MinLength: 1, MaxLength: 80, Pattern: "^[A-Za-z ]+$"}},
+			// a $ref to a value schema is inlined
+			{Name: "Status", Type: "enum", JsonName: "status", EnumValues: []string{"available", "pending", "sold"},
+				GormTags: []string{"not null"}, Validation: &domain.FieldValidation{Required: true}},
+			{Name: "Size", Type: "enum", JsonName: "size", EnumValues: []string{"s", "m", "l"}, DefaultVal: "m"},
+			{Name: "Birthday", Type: "date", JsonName: "birthday"},
+			{Name: "CheckedAt", Type: "time.Time", JsonName: "checkedAt"},
+			{Name: "Chip", Type: "uuid", JsonName: "chip"},
+			{Name: "Photo", Type: "file", JsonName: "photo"},
+			{Name: "Weight", Type: "float64", JsonName: "weight", Validation: &domain.FieldValidation{Min: float(0)}},
+			{Name: "Price", Type: "decimal", JsonName: "price"},
+			// required, but a checkbox cannot be left unset
+			{Name: "Vaccinated", Type: "bool", JsonName: "vaccinated", GormTags: []string{"not null"}},
+			{Name: "Notes", Type: "text", JsonName: "notes", Validation: &domain.FieldValidation{MaxLength: 10000}},
+			{Name: "Attributes", Type: "json", JsonName: "attributes"},
+			// required but nullable
+			{Name: "Nickname", Type: "string", JsonName: "nickname"},
+			// the foreign key of the category relation: its minimum is left to the select
+			{Name: "CategoryID", Type: "uint", JsonName: "category_id"},
+			{Name: "Vet", Type: "json", JsonName: "vet"},
+		}, Relations: []domain.RelationDef{
+			{Name: "Category", Type: domain.RelationBelongsTo, Model: "Category"},
+			{Name: "Owner", Type: domain.RelationBelongsTo, Model: "Owner"},
+			{Name: "Tags", Type: domain.RelationMany2Many, Model: "Tag"},
+		}},
+	})
+
+	want := []string{"Pet.vet: unresolved reference #/components/schemas/Vet imported as json"}
+	if !slices.Equal(result.Warnings, want) {
+		t.Errorf("warnings %q, want %q", result.Warnings, want)
+	}
+}
+
+func TestImportJSONSchema(t *testing.T) {
+	result := importFixture(t, "order.schema.json", "")
+
+	checkModels(t, result.Models, []domain.ModelDef{
+		{Name: "Customer", Fields: []domain.FieldDef{
+			{Name: "ID", Type: "uuid", GormTags: []string{"primaryKey"}, JsonName: "id"},
+			{Name: "Name", Type: "string", JsonName: "name"},
+		}},
+		{Name: "Line", Fields: []domain.FieldDef{
+			{Name: "ID", Type: "uint", GormTags: []string{"primaryKey"}, JsonName: "id"},
+			{Name: "SKU", Type: "string", JsonName: "sku"},
+			{Name: "Qty", Type: "int", JsonName: "qty", Validation: &domain.FieldValidation{Min: float(1), Max: float(99)}},
+		}},
+		// the root schema, named after its title
+		{Name: "PurchaseOrder", Fields: []domain.FieldDef{
+			{Name: "ID", Type: "uint", GormTags: []string{"primaryKey"}, JsonName: "id"},
+			{Name: "Number", Type: "string", JsonName: "number", GormTags: []string{"not null"},
+				Validation: &domain.FieldValidation{Required: true}},
+			{Name: "Total", Type: "float64", JsonName: "total"},
+			{Name: "Code", Type: "json", JsonName: "code"},
+		}, Relations: []domain.RelationDef{
+			{Name: "Lines", Type: domain.RelationMany2Many, Model: "Line"},
+		}},
+	})
+
+	want := []string{
+		"Customer.id: primary key is uuid; relations to this schema are not imported",
+		"PurchaseOrder.code: union type string | integer imported as json",
+		"PurchaseOrder.customer: references Customer, whose primary key is not an integer",
+	}
+	if !slices.Equal(result.Warnings, want) {
+		t.Errorf("warnings %q, want %q", result.Warnings, want)
+	}
+}
+
+func TestImportOpenAPIErrors(t *testing.T) {
+	for name, document := range map[string]string{
+		"not YAML":   "components: [",
+		"no schemas": "openapi: 3.0.0\ninfo: {title: x}\n",
+	} {
+		if _, err := ImportOpenAPI([]byte(document), ""); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package introspect
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"ggami-go/internal/domain"
+)
+
+// sqliteReader reads the catalog through SQLite PRAGMAs
+type sqliteReader struct {
+	db *sql.DB
+}
+
+func (r sqliteReader) tableNames(ctx context.Context) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT name FROM sqlite_master
+WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name <> 'schema_migrations'
+ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	return scanStrings(rows)
+}
+
+// tableInfo is one row of PRAGMA table_info
+type tableInfo struct {
+	cid     int
+	name    string
+	typ     string
+	notNull bool
+	dflt    sql.NullString
+	pk      int // position in the primary key, 0 if not part of it
+}
+
+func (r sqliteReader) tableInfo(ctx context.Context, table string) ([]tableInfo, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT cid, name, type, \"notnull\", dflt_value, pk FROM pragma_table_info(?)", table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []tableInfo
+	for rows.Next() {
+		var ti tableInfo
+		if err := rows.Scan(&ti.cid, &ti.name, &ti.typ, &ti.notNull, &ti.dflt, &ti.pk); err != nil {
+			return nil, err
+		}
+		out = append(out, ti)
+	}
+	return out, rows.Err()
+}
+
+func (r sqliteReader) columns(ctx context.Context, table string) ([]rawColumn, error) {
+	infos, err := r.tableInfo(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	pkCount := 0
+	for _, ti := range infos {
+		if ti.pk > 0 {
+			pkCount++
+		}
+	}
+	var out []rawColumn
+	for _, ti := range infos {
+		out = append(out, rawColumn{
+			Name:    ti.name,
+			Type:    ti.typ,
+			NotNull: ti.notNull || ti.pk > 0,
+			Default: ti.dflt,
+			// a single INTEGER PRIMARY KEY is an alias of rowid
+			AutoIncrement: ti.pk > 0 && pkCount == 1 && strings.EqualFold(ti.typ, "INTEGER"),
+		})
+	}
+	return out, nil
+}
+
+func (r sqliteReader) primaryKey(ctx context.Context, table string) ([]string, error) {
+	infos, err := r.tableInfo(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	var keyed []tableInfo
+	for _, ti := range infos {
+		if ti.pk > 0 {
+			keyed = append(keyed, ti)
+		}
+	}
+	sort.Slice(keyed, func(i, j int) bool { return keyed[i].pk < keyed[j].pk })
+	var out []string
+	for _, ti := range keyed {
+		out = append(out, ti.name)
+	}
+	return out, nil
+}
+
+func (r sqliteReader) indexes(ctx context.Context, table string) ([]domain.SQLIndex, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT il.name, ii.name, il."unique"
+FROM pragma_index_list(?) AS il, pragma_index_info(il.name) AS ii
+WHERE il.origin <> 'pk'
+ORDER BY il.name, ii.seqno`, table)
+	if err != nil {
+		return nil, err
+	}
+	return groupIndexes(rows)
+}
+
+func (r sqliteReader) foreignKeys(ctx context.Context, table string) ([]rawForeignKey, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, "from", "table", "to", on_delete FROM pragma_foreign_key_list(?) ORDER BY id, seq`, table)
+	if err != nil {
+		return nil, err
+	}
+	var out []rawForeignKey
+	for rows.Next() {
+		var id int
+		var fk rawForeignKey
+		var to sql.NullString
+		if err := rows.Scan(&id, &fk.Column, &fk.RefTable, &to, &fk.OnDelete); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		// SQLite foreign keys are unnamed
+		fk.Name = fmt.Sprintf("fk_%s_%d", table, id)
+		fk.RefColumn = to.String
+		out = append(out, fk)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// "to" is NULL when the parent's primary key is meant
+	for i, fk := range out {
+		if fk.RefColumn != "" {
+			continue
+		}
+		pk, err := r.primaryKey(ctx, fk.RefTable)
+		if err != nil {
+			return nil, err
+		}
+		if len(pk) == 1 {
+			out[i].RefColumn = pk[0]
+		}
+	}
+	return out, nil
+}
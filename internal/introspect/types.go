@@ -0,0 +1,123 @@
+package introspect
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"ggami-go/internal/domain"
+)
+
+var sizePattern = regexp.MustCompile(`\(\s*(\d+)\s*\)`)
+
+// classify maps a declared column type to a field type registry name and
+// size. Returns "" for types without a matching field type.
+func classify(dbType domain.DBType, rawType string) (string, int) {
+	t := strings.ToLower(strings.TrimSpace(rawType))
+	if strings.HasSuffix(t, "[]") {
+		return "", 0 // arrays have no field type
+	}
+	base := t
+	if i := strings.IndexAny(base, "( "); i >= 0 {
+		base = base[:i]
+	}
+	size := 0
+	if m := sizePattern.FindStringSubmatch(t); m != nil {
+		size, _ = strconv.Atoi(m[1])
+	}
+
+	switch base {
+	case "tinyint":
+		if dbType == domain.DBTypeMySQL && size == 1 {
+			return "bool", 0
+		}
+		fallthrough
+	case "int", "integer", "smallint", "mediumint", "bigint", "int2", "int4", "int8", "serial", "bigserial", "smallserial":
+		if strings.Contains(t, "unsigned") {
+			return "uint", 0
+		}
+		return "int", 0
+	case "bool", "boolean", "bit":
+		return "bool", 0
+	case "decimal", "numeric", "money", "smallmoney":
+		return "decimal", 0
+	case "real", "float", "double", "float4", "float8":
+		return "float64", 0
+	case "varchar", "nvarchar", "char", "nchar", "character", "varchar2":
+		if strings.Contains(t, "(max)") {
+			return "text", 0
+		}
+		return "string", size
+	case "text", "ntext", "tinytext", "mediumtext", "longtext", "clob":
+		if dbType == domain.DBTypeSQLite {
+			return "string", 0 // SQLite stores every string as TEXT
+		}
+		return "text", 0
+	case "enum":
+		return "enum", 0
+	case "uuid", "uniqueidentifier":
+		return "uuid", 0
+	case "json", "jsonb":
+		return "json", 0
+	case "date":
+		return "date", 0
+	case "datetime", "datetime2", "datetimeoffset", "smalldatetime", "timestamp", "timestamptz":
+		return "time.Time", 0
+	}
+
+	switch {
+	case strings.HasPrefix(t, "character varying"):
+		return "string", size
+	case strings.HasPrefix(t, "double precision"):
+		return "float64", 0
+	case strings.HasPrefix(t, "timestamp"):
+		return "time.Time", 0
+	}
+	return "", 0
+}
+
+var enumValue = regexp.MustCompile(`'((?:[^']|'')*)'`)
+
+// enumValues returns the values of a MySQL ENUM('a','b') type
+func enumValues(rawType string) []string {
+	var values []string
+	for _, m := range enumValue.FindAllStringSubmatch(rawType, -1) {
+		values = append(values, strings.ReplaceAll(m[1], "''", "'"))
+	}
+	return values
+}
+
+var (
+	castSuffix    = regexp.MustCompile(`(?i)::[a-z ]+(\[\])?$`)
+	numberLiteral = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+)
+
+// defaultValue normalises a catalog default to the unquoted value the
+// generator stores in DefaultVal. Returns false for expressions.
+func defaultValue(dbType domain.DBType, raw string) (string, bool) {
+	v := strings.TrimSpace(raw)
+	// SQL Server wraps defaults in parentheses: ((0)), ('x')
+	for strings.HasPrefix(v, "(") && strings.HasSuffix(v, ")") {
+		v = strings.TrimSpace(v[1 : len(v)-1])
+	}
+	v = castSuffix.ReplaceAllString(v, "")
+	if strings.EqualFold(v, "NULL") {
+		return "", true
+	}
+	if strings.HasPrefix(v, "N'") {
+		v = v[1:]
+	}
+
+	switch {
+	case len(v) >= 2 && v[0] == '\'' && v[len(v)-1] == '\'':
+		return strings.ReplaceAll(v[1:len(v)-1], "''", "'"), true
+	case numberLiteral.MatchString(v):
+		return v, true
+	case strings.EqualFold(v, "true"), strings.EqualFold(v, "false"):
+		return strings.ToLower(v), true
+	case dbType == domain.DBTypeMySQL && !strings.Contains(v, "(") && !strings.HasPrefix(strings.ToUpper(v), "CURRENT_") && !strings.HasPrefix(strings.ToUpper(v), "LOCALTIME"):
+		// MySQL reports string defaults without quotes
+		return v, true
+	}
+	return "", false
+}